@@ -0,0 +1,71 @@
+package ansi
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// An AlertElement is used to render alerts like [!NOTE] or [!WARNING]. It
+// renders like a block quote with a title line, drawing its border and title
+// in the alert's accent style.
+type AlertElement struct {
+	Type  string
+	Title string
+}
+
+// alertStyle returns the style for an alert type, falling back to the block
+// quote style for types without a style of their own.
+func alertStyle(ctx RenderContext, alertType string) StyleAlert {
+	if s, ok := ctx.options.Styles.Alerts[alertType]; ok {
+		return s
+	}
+	return StyleAlert{
+		StyleBlock: ctx.options.Styles.BlockQuote,
+		Title:      strings.ToUpper(alertType[:1]) + alertType[1:],
+	}
+}
+
+func (e *AlertElement) block(ctx RenderContext) *BlockElement {
+	rules := alertStyle(ctx, e.Type)
+	accent := cascadeStylePrimitives(ctx.blockStack.Current().Style.StylePrimitive, rules.Accent)
+
+	return &BlockElement{
+		Block:       &bytes.Buffer{},
+		Style:       cascadeStyle(ctx.blockStack.Current().Style, rules.StyleBlock, false),
+		Margin:      true,
+		IndentStyle: &accent,
+	}
+}
+
+// Render renders an AlertElement.
+func (e *AlertElement) Render(w io.Writer, ctx RenderContext) error {
+	rules := alertStyle(ctx, e.Type)
+	if err := e.block(ctx).Render(w, ctx); err != nil {
+		return err
+	}
+
+	title := e.Title
+	if title == "" {
+		title = rules.Title
+	}
+	if rules.Icon != "" {
+		title = rules.Icon + " " + title
+	}
+
+	bs := ctx.blockStack
+	el := &BaseElement{
+		Token: title,
+		Style: rules.Accent,
+	}
+	if err := el.Render(bs.Current().Block, ctx); err != nil {
+		return err
+	}
+	_, _ = io.WriteString(bs.Current().Block, "\n")
+	return nil
+}
+
+// Finish finishes rendering an AlertElement.
+func (e *AlertElement) Finish(w io.Writer, ctx RenderContext) error {
+	return e.block(ctx).Finish(w, ctx)
+}
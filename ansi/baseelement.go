@@ -20,10 +20,13 @@ type BaseElement struct {
 	Style  StylePrimitive
 }
 
-func formatToken(format string, token string) (string, error) {
+func formatToken(format string, token string, vars map[string]interface{}) (string, error) {
 	var b bytes.Buffer
 
 	v := make(map[string]interface{})
+	for k, val := range vars {
+		v[k] = val
+	}
 	v["text"] = token
 
 	tmpl, err := template.New(format).Funcs(TemplateFuncMap).Parse(format)
@@ -40,45 +43,26 @@ func renderText(w io.Writer, p termenv.Profile, rules StylePrimitive, s string)
 		return
 	}
 
-	out := termenv.String(s)
 	if rules.Upper != nil && *rules.Upper {
-		out = termenv.String(cases.Upper(language.English).String(s))
+		s = cases.Upper(language.English).String(s)
 	}
 	if rules.Lower != nil && *rules.Lower {
-		out = termenv.String(cases.Lower(language.English).String(s))
+		s = cases.Lower(language.English).String(s)
 	}
 	if rules.Title != nil && *rules.Title {
-		out = termenv.String(cases.Title(language.English).String(s))
-	}
-	if rules.Color != nil {
-		out = out.Foreground(p.Color(*rules.Color))
-	}
-	if rules.BackgroundColor != nil {
-		out = out.Background(p.Color(*rules.BackgroundColor))
-	}
-	if rules.Underline != nil && *rules.Underline {
-		out = out.Underline()
-	}
-	if rules.Bold != nil && *rules.Bold {
-		out = out.Bold()
-	}
-	if rules.Italic != nil && *rules.Italic {
-		out = out.Italic()
-	}
-	if rules.CrossedOut != nil && *rules.CrossedOut {
-		out = out.CrossOut()
-	}
-	if rules.Overlined != nil && *rules.Overlined {
-		out = out.Overline()
-	}
-	if rules.Inverse != nil && *rules.Inverse {
-		out = out.Reverse()
-	}
-	if rules.Blink != nil && *rules.Blink {
-		out = out.Blink()
+		s = cases.Title(language.English).String(s)
 	}
 
-	_, _ = io.WriteString(w, out.String())
+	_, _ = io.WriteString(w, newSGRStyle(p, rules).styled(s))
+}
+
+// prefixStyle returns the style a block's prefix is rendered in: the block's
+// own style, colored in its prefix_color if it has one.
+func prefixStyle(rules StylePrimitive) StylePrimitive {
+	if rules.PrefixColor != nil {
+		rules.Color = rules.PrefixColor
+	}
+	return rules
 }
 
 // StyleOverrideRender renders a BaseElement with an overridden style.
@@ -87,7 +71,7 @@ func (e *BaseElement) StyleOverrideRender(w io.Writer, ctx RenderContext, style
 	st1 := cascadeStylePrimitives(bs.Current().Style.StylePrimitive, style)
 	st2 := cascadeStylePrimitives(bs.With(e.Style), style)
 
-	return e.doRender(w, ctx.options.ColorProfile, st1, st2)
+	return e.doRender(w, ctx, st1, st2)
 }
 
 // Render renders a BaseElement.
@@ -95,22 +79,20 @@ func (e *BaseElement) Render(w io.Writer, ctx RenderContext) error {
 	bs := ctx.blockStack
 	st1 := bs.Current().Style.StylePrimitive
 	st2 := bs.With(e.Style)
-	return e.doRender(w, ctx.options.ColorProfile, st1, st2)
+	return e.doRender(w, ctx, st1, st2)
 }
 
-func (e *BaseElement) doRender(w io.Writer, p termenv.Profile, st1, st2 StylePrimitive) error {
+func (e *BaseElement) doRender(w io.Writer, ctx RenderContext, st1, st2 StylePrimitive) error {
+	p := ctx.options.ColorProfile
 
-	prefixStyle := st1 // Use the element style as the base st1 is the parent
-	prefixStyle.Color = st1.PrefixColor
-	renderText(w, p, prefixStyle, e.Prefix)
+	// st1 is the parent's style
+	renderText(w, p, prefixStyle(st1), e.Prefix)
 
 	//renders the block_prefix of elements (e.g., ordered and unordered lists, definition lists) in the style
 	// of the parent using the prefix_color of the parent to color the block_prefix
-	blockPrefixStyle := st1 // Use the element style of the parent
-	blockPrefixStyle.Color = st1.PrefixColor
-	renderText(w, p, blockPrefixStyle, st2.BlockPrefix)
+	renderText(w, p, prefixStyle(st1), st2.BlockPrefix)
 	defer func() {
-		renderText(w, p, blockPrefixStyle, st2.BlockSuffix)
+		renderText(w, p, prefixStyle(st1), st2.BlockSuffix)
 	}()
 
 	// render styled prefix/suffix
@@ -123,7 +105,9 @@ func (e *BaseElement) doRender(w io.Writer, p termenv.Profile, st1, st2 StylePri
 	s := e.Token
 	if len(st2.Format) > 0 {
 		var err error
-		s, err = formatToken(st2.Format, s)
+		s, err = formatToken(st2.Format, s, map[string]interface{}{
+			"meta": ctx.meta,
+		})
 		if err != nil {
 			return err
 		}
@@ -151,5 +135,6 @@ var escapeReplacer = strings.NewReplacer(
 	"\\-", "-",
 	"\\.", ".",
 	"\\!", "!",
+	"\\$", "$",
 	"\\|", "|",
 )
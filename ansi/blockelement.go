@@ -16,6 +16,9 @@ type BlockElement struct {
 	Style   StyleBlock
 	Margin  bool
 	Newline bool
+
+	// IndentStyle overrides the style used to render the indent token.
+	IndentStyle *StylePrimitive
 }
 
 // Render renders a BlockElement.
@@ -24,7 +27,7 @@ func (e *BlockElement) Render(w io.Writer, ctx RenderContext) error {
 	bs.Push(*e)
 
 	renderText(w, ctx.options.ColorProfile, bs.Parent().Style.StylePrimitive, e.Style.BlockPrefix)
-	renderText(bs.Current().Block, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, e.Style.Prefix)
+	renderText(bs.Current().Block, ctx.options.ColorProfile, prefixStyle(bs.Current().Style.StylePrimitive), e.Style.Prefix)
 	return nil
 }
 
@@ -39,7 +42,11 @@ func (e *BlockElement) Finish(w io.Writer, ctx RenderContext) error {
 			" ,.;-+|",
 		)
 
-		mw := NewMarginWriter(ctx, w, bs.Current().Style)
+		indentStyle := bs.Parent().Style.StylePrimitive
+		if bs.Current().IndentStyle != nil {
+			indentStyle = *bs.Current().IndentStyle
+		}
+		mw := newMarginWriter(ctx, w, bs.Current().Style, indentStyle)
 		if _, err := io.WriteString(mw, s); err != nil {
 			return fmt.Errorf("glamour: error writing to writer: %w", err)
 		}
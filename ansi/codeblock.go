@@ -1,13 +1,18 @@
 package ansi
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/quick"
 	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/glamour/internal/fence"
+	xansi "github.com/charmbracelet/x/ansi"
 	"github.com/muesli/reflow/indent"
 	"github.com/muesli/termenv"
 )
@@ -28,8 +33,34 @@ var mutex = sync.Mutex{}
 type CodeBlockElement struct {
 	Code     string
 	Language string
+
+	// Info holds the attributes of a fenced code block's info string: its
+	// title, line numbers and highlighted lines.
+	Info CodeBlockInfo
 }
 
+// CodeBlockInfo holds the attributes of a fenced code block's info string,
+// such as its title, line numbers and highlighted lines.
+type CodeBlockInfo = fence.Info
+
+// A LineRange is an inclusive range of line numbers.
+type LineRange = fence.Range
+
+// CodeOverflow defines how code lines exceeding the available width are
+// rendered.
+type CodeOverflow int
+
+// Code overflow policies.
+const (
+	// CodeOverflowNone leaves long lines as is.
+	CodeOverflowNone CodeOverflow = iota
+	// CodeOverflowWrap wraps long lines, marking continued lines in the
+	// gutter.
+	CodeOverflowWrap
+	// CodeOverflowTruncate truncates long lines with an ellipsis.
+	CodeOverflowTruncate
+)
+
 func chromaStyle(style StylePrimitive) string {
 	var s string
 
@@ -130,23 +161,274 @@ func (e *CodeBlockElement) Render(w io.Writer, ctx RenderContext) error {
 	iw := indent.NewWriterPipe(w, indentation+margin, func(_ io.Writer) {
 		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, " ")
 	})
+	width := int(bs.Width(ctx)) - int(indentation+margin) //nolint: gosec
+
+	border, framed := borderStyles[rules.Border]
+	if framed && width-frameWidth > 0 {
+		// framed code gets rendered to a buffer first and is then drawn
+		// inside the border, with the title or language as its label
+		var buf bytes.Buffer
+		if err := e.renderCode(&buf, ctx, width-frameWidth, formatter, theme); err != nil {
+			return err
+		}
+		return e.renderFrame(iw, ctx, border, buf.String(), width)
+	}
+
+	if e.Info.Title != "" {
+		el := &BaseElement{
+			Token: e.Info.Title,
+			Style: rules.Title,
+		}
+		if err := el.Render(iw, ctx); err != nil {
+			return err
+		}
+		_, _ = io.WriteString(iw, "\n")
+	}
+	return e.renderCode(iw, ctx, width, formatter, theme)
+}
+
+// renderCode renders the code, highlighted by Chroma if a theme is set, and
+// decorated with its gutter and line backgrounds.
+func (e *CodeBlockElement) renderCode(w io.Writer, ctx RenderContext, width int, formatter, theme string) error {
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.CodeBlock
+
+	// lines get measured to pad and wrap them, which needs tabs expanded
+	tabWidth := ctx.options.TabWidth
+	if tabWidth <= 0 {
+		tabWidth = defaultTabWidth
+	}
+	code := expandTabs(e.Code, tabWidth)
+	language := e.Language
+	if d := ctx.options.LanguageDetection; d != nil {
+		language = d.language(language, code)
+	}
+	if s := ctx.options.StructuredData; s != nil {
+		if lines, ok := s.tree(language, code, width); ok {
+			el := &BaseElement{
+				Token: strings.Join(lines, "\n") + "\n",
+				Style: rules.StylePrimitive,
+			}
+			return el.Render(w, ctx)
+		}
+		code = s.pretty(language, code)
+	}
+
+	// decorated code gets rendered to a buffer first and is then written
+	// line by line, next to its gutter
+	var cw io.Writer = w
+	var buf bytes.Buffer
+	decorated := e.Info.LineNumbers || len(e.Info.Highlight) > 0 || ctx.options.CodeOverflow != CodeOverflowNone
+	if decorated {
+		cw = &buf
+	}
+
+	if isDiff(language, code) {
+		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockPrefix)
+		text, backgrounds := renderDiff(ctx, code, formatter, theme)
+		e.renderLines(w, ctx, text, width, backgrounds)
+		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockSuffix)
+		return nil
+	}
 
 	if len(theme) > 0 {
-		renderText(iw, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockPrefix)
+		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockPrefix)
 
-		err := quick.Highlight(iw, e.Code, e.Language, formatter, theme)
+		err := quick.Highlight(cw, code, language, formatter, theme)
 		if err != nil {
 			return fmt.Errorf("glamour: error highlighting code: %w", err)
 		}
-		renderText(iw, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockSuffix)
+		if decorated {
+			e.renderLines(w, ctx, buf.String(), width, nil)
+		}
+		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockSuffix)
 		return nil
 	}
 
 	// fallback rendering
 	el := &BaseElement{
-		Token: e.Code,
+		Token: code,
 		Style: rules.StylePrimitive,
 	}
 
-	return el.Render(iw, ctx)
+	if err := el.Render(cw, ctx); err != nil {
+		return err
+	}
+	if decorated {
+		e.renderLines(w, ctx, buf.String(), width, nil)
+	}
+	return nil
+}
+
+// A codeRow is a line of rendered code, or a part of a wrapped line.
+type codeRow struct {
+	line      int
+	text      string
+	continued bool
+}
+
+// renderLines writes the rendered code to w, prefixing each line with the
+// gutter, highlighting the chosen lines and wrapping or truncating lines
+// exceeding the available width. Lines with a background, either highlighted
+// or from the given backgrounds, are painted across the block's width. The
+// gutter is written by an indent pipe, which restores the code's colors
+// after each gutter.
+func (e *CodeBlockElement) renderLines(w io.Writer, ctx RenderContext, code string, width int, backgrounds []string) {
+	p := ctx.options.ColorProfile
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.CodeBlock
+	overflow := ctx.options.CodeOverflow
+
+	lines := strings.Split(code, "\n")
+	// trailing escape sequences belong to the last line
+	for len(lines) > 1 && xansi.Strip(lines[len(lines)-1]) == "" {
+		lines[len(lines)-2] += lines[len(lines)-1]
+		lines = lines[:len(lines)-1]
+	}
+	last := e.Info.Start + len(lines) - 1
+	digits := len(strconv.Itoa(last))
+	if overflow == CodeOverflowWrap {
+		digits = max(digits, xansi.StringWidth(rules.WrapMarker))
+	}
+
+	// the highlight's prefix marks highlighted lines in the gutter
+	var marker string
+	if len(e.Info.Highlight) > 0 {
+		marker = rules.Highlight.Prefix
+	}
+
+	highlight := backgroundSequence(p, rules.Highlight.BackgroundColor)
+
+	gutterWidth := xansi.StringWidth(marker)
+	if e.Info.LineNumbers {
+		gutterWidth += xansi.StringWidth(rules.LineNumber.Prefix+rules.LineNumber.Suffix) + digits
+	}
+	codeWidth := width - gutterWidth
+	if codeWidth <= 0 {
+		overflow = CodeOverflowNone
+	}
+
+	// without line numbers, wrapped code needs a gutter column for the
+	// continuation marker
+	var contWidth int
+	if overflow == CodeOverflowWrap && !e.Info.LineNumbers && rules.WrapMarker != "" {
+		for _, l := range lines {
+			if xansi.StringWidth(l) > codeWidth {
+				contWidth = xansi.StringWidth(rules.WrapMarker) + 1
+				break
+			}
+		}
+		if codeWidth-contWidth > 0 {
+			codeWidth -= contWidth
+			gutterWidth += contWidth
+		} else {
+			contWidth = 0
+		}
+	}
+
+	rows := make([]codeRow, 0, len(lines))
+	for i, l := range lines {
+		n := e.Info.Start + i
+		switch {
+		case overflow == CodeOverflowWrap && xansi.StringWidth(l) > codeWidth:
+			for j, part := range strings.Split(xansi.Hardwrap(l, codeWidth, true), "\n") {
+				rows = append(rows, codeRow{line: n, text: part, continued: j > 0})
+			}
+		case overflow == CodeOverflowTruncate && xansi.StringWidth(l) > codeWidth:
+			rows = append(rows, codeRow{line: n, text: xansi.Truncate(l, codeWidth, rules.Ellipsis)})
+		default:
+			rows = append(rows, codeRow{line: n, text: l})
+		}
+	}
+
+	var i int
+	gw := indent.NewWriterPipe(w, 1, func(_ io.Writer) {
+		if i >= len(rows) {
+			return
+		}
+		r := rows[i]
+		i++
+
+		style := bs.Current().Style.StylePrimitive
+		hl := e.Info.Highlighted(r.line)
+		if hl {
+			renderText(w, p, cascadeStylePrimitives(style, rules.Highlight), marker)
+		} else {
+			renderText(w, p, style, strings.Repeat(" ", xansi.StringWidth(marker)))
+		}
+
+		style = cascadeStylePrimitives(style, rules.LineNumber)
+		if hl {
+			style = cascadeStylePrimitives(style, rules.Highlight)
+		}
+		switch {
+		case e.Info.LineNumbers:
+			n := strconv.Itoa(r.line)
+			if r.continued {
+				n = rules.WrapMarker
+			}
+			n = strings.Repeat(" ", max(digits-xansi.StringWidth(n), 0)) + n
+			renderText(w, p, style, rules.LineNumber.Prefix+n+rules.LineNumber.Suffix)
+		case contWidth > 0:
+			n := strings.Repeat(" ", contWidth)
+			if r.continued {
+				n = rules.WrapMarker + " "
+			}
+			renderText(w, p, style, n)
+		}
+	})
+
+	for _, r := range rows {
+		l := r.text
+		var bg string
+		if i := r.line - e.Info.Start; i < len(backgrounds) {
+			bg = backgrounds[i]
+		}
+		if highlight != "" && e.Info.Highlighted(r.line) {
+			bg = highlight
+		}
+		if bg != "" {
+			pad := max(width-gutterWidth-xansi.StringWidth(l), 0)
+			l = bg + strings.ReplaceAll(l, "\x1b[0m", "\x1b[0m"+bg) +
+				strings.Repeat(" ", pad) + "\x1b[0m"
+		}
+		_, _ = io.WriteString(gw, l+"\n")
+	}
+}
+
+// backgroundSequence returns the escape sequence setting the given
+// background color, or an empty string if the color can't be displayed.
+func backgroundSequence(p termenv.Profile, color *string) string {
+	if color == nil {
+		return ""
+	}
+	return sgrStyle{}.color(p.Color(*color), true).sequence()
+}
+
+// defaultTabWidth is the tab width of code blocks unless set otherwise.
+const defaultTabWidth = 8
+
+// expandTabs replaces tabs with spaces up to the next tab stop.
+func expandTabs(s string, tabWidth int) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+
+	var b strings.Builder
+	var col int
+	for _, r := range s {
+		switch r {
+		case '\t':
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		case '\n':
+			b.WriteRune(r)
+			col = 0
+		default:
+			b.WriteRune(r)
+			col += xansi.StringWidth(string(r))
+		}
+	}
+	return b.String()
 }
@@ -0,0 +1,138 @@
+package ansi
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+// frameWidth is the width the frame adds to a code block: a border and a
+// space of padding on either side.
+const frameWidth = 4
+
+// borderStyles maps the names of border styles to their borders. They are
+// used by framed code blocks and tables.
+var borderStyles = map[string]lipgloss.Border{
+	"normal":  lipgloss.NormalBorder(),
+	"rounded": lipgloss.RoundedBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"block":   lipgloss.BlockBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+	"ascii":   lipgloss.ASCIIBorder(),
+}
+
+// Label positions in a code block's top border.
+const (
+	LabelLeft   = "left"
+	LabelCenter = "center"
+	LabelRight  = "right"
+)
+
+// renderFrame draws the rendered code inside a border spanning the given
+// width, or the width of the code's longest line. The code block's title, or
+// else its language, is shown as a label in the top border.
+func (e *CodeBlockElement) renderFrame(w io.Writer, ctx RenderContext, border lipgloss.Border, code string, width int) error {
+	p := ctx.options.ColorProfile
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.CodeBlock
+
+	borderStyle := bs.With(rules.StylePrimitive)
+	if rules.BorderColor != nil {
+		borderStyle.Color = rules.BorderColor
+	}
+
+	lines := strings.Split(code, "\n")
+	// trailing escape sequences belong to the last line
+	for len(lines) > 1 && xansi.Strip(lines[len(lines)-1]) == "" {
+		lines[len(lines)-2] += lines[len(lines)-1]
+		lines = lines[:len(lines)-1]
+	}
+	inner := width - frameWidth
+	for _, l := range lines {
+		inner = max(inner, xansi.StringWidth(l))
+	}
+	span := inner + 2 //nolint: mnd
+
+	// top border with label
+	var label bytes.Buffer
+	text := e.Info.Title
+	if text == "" {
+		text = e.Language
+	}
+	if text != "" {
+		el := &BaseElement{
+			Token: text,
+			Style: rules.Label,
+		}
+		if err := el.Render(&label, ctx); err != nil {
+			return err
+		}
+	}
+	labelWidth := xansi.StringWidth(label.String())
+	if labelWidth > span-2 {
+		label.Reset()
+		labelWidth = 0
+	}
+
+	var left int
+	if labelWidth > 0 {
+		switch rules.LabelPosition {
+		case LabelCenter:
+			left = (span - labelWidth) / 2 //nolint: mnd
+		case LabelRight:
+			left = span - labelWidth - 1
+		default:
+			left = 1
+		}
+	}
+	renderText(w, p, borderStyle, border.TopLeft+strings.Repeat(border.Top, left))
+	_, _ = w.Write(label.Bytes())
+	renderText(w, p, borderStyle, strings.Repeat(border.Top, span-left-labelWidth)+border.TopRight)
+	_, _ = io.WriteString(w, "\n")
+
+	// the code's colors are carried from line to line, as the border resets
+	// them
+	var state string
+	for _, l := range lines {
+		renderText(w, p, borderStyle, border.Left)
+		renderText(w, p, bs.Current().Style.StylePrimitive, " ")
+		_, _ = io.WriteString(w, state+l)
+		state = sgrState(state, l)
+		if state != "" {
+			_, _ = io.WriteString(w, "\x1b[0m")
+		}
+		renderText(w, p, bs.Current().Style.StylePrimitive, strings.Repeat(" ", inner-xansi.StringWidth(l)+1))
+		renderText(w, p, borderStyle, border.Right)
+		_, _ = io.WriteString(w, "\n")
+	}
+
+	renderText(w, p, borderStyle, border.BottomLeft+strings.Repeat(border.Bottom, span)+border.BottomRight)
+	_, _ = io.WriteString(w, "\n")
+	return nil
+}
+
+// sgrState returns the SGR sequences in effect after the given text, starting
+// from the given state.
+func sgrState(state, s string) string {
+	for {
+		i := strings.Index(s, "\x1b[")
+		if i < 0 {
+			return state
+		}
+		j := strings.IndexByte(s[i:], 'm')
+		if j < 0 {
+			return state
+		}
+		seq := s[i : i+j+1]
+		if seq == "\x1b[0m" || seq == "\x1b[m" {
+			state = ""
+		} else {
+			state += seq
+		}
+		s = s[i+j+1:]
+	}
+}
@@ -2,8 +2,10 @@ package ansi
 
 import (
 	"html"
+	"io"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/microcosm-cc/bluemonday"
 )
 
@@ -13,19 +15,47 @@ type RenderContext struct {
 
 	blockStack *BlockStack
 	table      *TableElement
+	headings   *headingCounter
+
+	// meta holds the document's front matter, made available to Format
+	// templates as {{.meta}}.
+	meta map[string]interface{}
 
 	stripper *bluemonday.Policy
 
+	// lipgloss renders lipgloss styles in the configured color profile.
+	lipgloss *lipgloss.Renderer
+
 	kittyImageConfig *KittyImageConfig // For kitty terminal image rendering
+
+	// folds holds the fold state of <details> sections.
+	folds *foldState
+
+	// err holds the error resolving the style's palette.
+	err error
 }
 
 // NewRenderContext returns a new RenderContext.
 func NewRenderContext(options Options) RenderContext {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(options.ColorProfile)
+
+	// a color missing from the palette fails rendering, like it fails
+	// glamour.NewTermRenderer
+	styles, err := ResolvePalette(options.Styles, nil)
+	if err == nil {
+		options.Styles = styles
+	}
+
 	return RenderContext{
 		options:    options,
+		err:        err,
 		blockStack: &BlockStack{},
 		table:      &TableElement{},
+		headings:   &headingCounter{},
 		stripper:   bluemonday.StrictPolicy(),
+		lipgloss:   r,
+		folds:      &foldState{},
 	}
 }
 
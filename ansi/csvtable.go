@@ -0,0 +1,128 @@
+package ansi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/glamour/internal/csvtable"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/indent"
+	astext "github.com/yuin/goldmark/extension/ast"
+)
+
+// csvSeparators maps the languages rendered as tables to their field
+// separators.
+var csvSeparators = map[string]rune{
+	"csv": ',',
+	"tsv": '\t',
+}
+
+// A CSVTableElement is used to render CSV and TSV code blocks as tables.
+// Data that can't be parsed is rendered as a code block instead.
+type CSVTableElement struct {
+	Code     string
+	Language string
+	Comma    rune
+	Header   csvtable.Header
+}
+
+// Render renders a CSVTableElement.
+func (e *CSVTableElement) Render(w io.Writer, ctx RenderContext) error {
+	data, err := csvtable.Parse(e.Code, e.Comma, e.Header)
+	if err != nil {
+		el := &CodeBlockElement{
+			Code:     e.Code,
+			Language: e.Language,
+		}
+		return el.Render(w, ctx)
+	}
+
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.Table
+
+	var indentation, margin uint
+	if rules.Indent != nil {
+		indentation = *rules.Indent
+	}
+	if rules.Margin != nil {
+		margin = *rules.Margin
+	}
+
+	iw := indent.NewWriterPipe(w, indentation+margin, func(_ io.Writer) {
+		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, " ")
+	})
+
+	style := bs.With(rules.StylePrimitive)
+	renderText(iw, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockPrefix)
+	renderText(iw, ctx.options.ColorProfile, style, rules.Prefix)
+
+	cell := func(s string, head bool, col int) (string, error) {
+		var b bytes.Buffer
+		el := &BaseElement{
+			Token: s,
+			Style: tableCellPrimitive(ctx, head, col),
+		}
+		if err := el.Render(&b, ctx); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	var header []string
+	if data.Header != nil {
+		header = make([]string, 0, len(data.Header))
+		for col, s := range data.Header {
+			c, err := cell(s, true, col)
+			if err != nil {
+				return err
+			}
+			header = append(header, c)
+		}
+	}
+	rows := make([][]string, 0, len(data.Rows))
+	for _, r := range data.Rows {
+		row := make([]string, 0, len(r))
+		for col, s := range r {
+			c, err := cell(s, false, col)
+			if err != nil {
+				return err
+			}
+			row = append(row, c)
+		}
+		rows = append(rows, row)
+	}
+
+	if ctx.options.TableNumbers != nil {
+		if rows, err = formatTableNumbers(ctx, rows, data.Numeric); err != nil {
+			return err
+		}
+	}
+
+	styleFunc := func(row, col int) lipgloss.Style {
+		if data.Numeric[col] {
+			return tableCellStyle(ctx, row, astext.AlignRight)
+		}
+		return tableCellStyle(ctx, row, astext.AlignNone)
+	}
+	t := newTable(ctx).Rows(paintTableRows(ctx, rows)...).StyleFunc(styleFunc)
+	if header != nil {
+		t.Headers(header...)
+	}
+	setTableBorders(ctx, t)
+
+	out := t.String()
+	if cards, ok := tableCards(ctx, header, rows, styleFunc); ok {
+		out = cards
+	} else if pages, ok := tablePages(ctx, header, rows, styleFunc); ok {
+		out = pages
+	}
+	if _, err := io.WriteString(w, out); err != nil {
+		return fmt.Errorf("glamour: error writing to buffer: %w", err)
+	}
+
+	renderText(w, ctx.options.ColorProfile, style, rules.Suffix)
+	renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockSuffix)
+	_, err = io.WriteString(w, "\n")
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,232 @@
+package ansi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Folds maps <details> sections to whether they're expanded. Sections are
+// keyed by their id attribute, or by their zero-based index in the document
+// ("0", "1", …) if they have none.
+type Folds map[string]bool
+
+// A Fold describes a rendered <details> section.
+type Fold struct {
+	// Index is the section's zero-based position in the document.
+	Index int
+
+	// ID is the section's id attribute.
+	ID string
+
+	// Summary is the plain text of the section's summary.
+	Summary string
+
+	// Expanded is set if the section's body was rendered.
+	Expanded bool
+
+	// StartLine and EndLine are the zero-based output lines of the summary
+	// and of the end of the body. They're equal for collapsed sections.
+	StartLine int
+	EndLine   int
+}
+
+// Key returns the key of the section in a Folds map.
+func (f Fold) Key() string {
+	if f.ID != "" {
+		return f.ID
+	}
+	return strconv.Itoa(f.Index)
+}
+
+// foldState holds the fold map of the current render, and the sections
+// rendered while tracking them.
+type foldState struct {
+	expanded Folds
+	track    bool
+	folds    []Fold
+}
+
+// Fold markers are written around tracked sections, so their lines can be
+// found once the output has been wrapped and indented. They're made of zero
+// width characters encoding whether they start or end a section and its
+// index in binary, and removed by FinishFolds. Escape sequences would make
+// the margin writers add resets.
+const (
+	foldZero      = '\u200b' // zero width space
+	foldOne       = '\u200c' // zero width non-joiner
+	foldIndexBits = 16
+)
+
+var (
+	foldMarkerPrefix = string([]rune{foldZero, foldOne, foldOne, foldZero})
+	foldMarkerRe     = regexp.MustCompile(fmt.Sprintf("%s[%c%c]{%d}", foldMarkerPrefix, foldZero, foldOne, foldIndexBits+1))
+)
+
+func foldMarker(index int, end bool) string {
+	bit := func(set bool) rune {
+		if set {
+			return foldOne
+		}
+		return foldZero
+	}
+
+	m := []rune(foldMarkerPrefix)
+	m = append(m, bit(end))
+	for i := foldIndexBits - 1; i >= 0; i-- {
+		m = append(m, bit(index>>i&1 == 1))
+	}
+	return string(m)
+}
+
+// parseFoldMarker returns the section index encoded in a fold marker, and
+// whether it ends the section.
+func parseFoldMarker(m []byte) (int, bool) {
+	bits := []rune(string(m))[len([]rune(foldMarkerPrefix)):]
+	index := 0
+	for _, r := range bits[1:] {
+		index <<= 1
+		if r == foldOne {
+			index |= 1
+		}
+	}
+	return index, bits[0] == foldOne
+}
+
+// StartFolds makes the following render expand or collapse <details>
+// sections according to folds, and track their positions. Sections missing
+// from folds keep their default state.
+func (r *ANSIRenderer) StartFolds(folds Folds) {
+	*r.context.folds = foldState{
+		expanded: folds,
+		track:    true,
+	}
+}
+
+// FinishFolds returns the output of a render started with StartFolds without
+// its fold markers, along with the rendered <details> sections.
+func (r *ANSIRenderer) FinishFolds(out []byte) ([]byte, []Fold) {
+	folds := r.context.folds.folds
+	*r.context.folds = foldState{}
+
+	byIndex := make(map[int]*Fold, len(folds))
+	for i := range folds {
+		byIndex[folds[i].Index] = &folds[i]
+	}
+
+	lines := bytes.Split(out, []byte("\n"))
+	for i, line := range lines {
+		for _, m := range foldMarkerRe.FindAll(line, -1) {
+			index, end := parseFoldMarker(m)
+			f, ok := byIndex[index]
+			if !ok {
+				continue
+			}
+			if end {
+				f.EndLine = i
+			} else {
+				f.StartLine = i
+			}
+		}
+		lines[i] = foldMarkerRe.ReplaceAll(line, nil)
+	}
+
+	for i := range folds {
+		// sections with an empty body end on their summary line
+		folds[i].EndLine = max(folds[i].EndLine, folds[i].StartLine)
+	}
+	sort.Slice(folds, func(i, j int) bool {
+		return folds[i].Index < folds[j].Index
+	})
+	return bytes.Join(lines, []byte("\n")), folds
+}
+
+// A DetailsElement is used to render <details> sections: a summary line
+// marked as collapsed or expanded, followed by the indented body of expanded
+// sections.
+type DetailsElement struct {
+	Index    int
+	ID       string
+	Summary  string
+	Expanded bool
+	First    bool
+}
+
+func (e *DetailsElement) block(ctx RenderContext) *BlockElement {
+	return &BlockElement{
+		Block:  &bytes.Buffer{},
+		Style:  cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.Details.StyleBlock, false),
+		Margin: true,
+	}
+}
+
+// Render renders a DetailsElement.
+func (e *DetailsElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.Details
+	style := cascadeStylePrimitives(bs.Current().Style.StylePrimitive, ctx.options.Styles.Summary)
+
+	summary, err := renderHTML(ctx, e.Summary, style, false)
+	if err != nil {
+		return err
+	}
+	summary = strings.Join(strings.Fields(strings.ReplaceAll(summary, "\n", " ")), " ")
+
+	marker := rules.Collapsed
+	if e.Expanded {
+		marker = rules.Expanded
+	}
+
+	if !e.First {
+		_, _ = io.WriteString(w, "\n")
+	}
+	if ctx.folds.track {
+		_, _ = io.WriteString(w, foldMarker(e.Index, false))
+		ctx.folds.folds = append(ctx.folds.folds, Fold{
+			Index:    e.Index,
+			ID:       e.ID,
+			Summary:  htmlPlainText(e.Summary),
+			Expanded: e.Expanded,
+			EndLine:  -1,
+		})
+	}
+	mw := NewMarginWriter(ctx, w, cascadeStyle(bs.Current().Style, StyleBlock{}, false))
+	renderText(mw, ctx.options.ColorProfile, style, marker)
+	_, _ = io.WriteString(mw, summary)
+	if !e.Expanded && ctx.folds.track {
+		_, _ = io.WriteString(mw, foldMarker(e.Index, true))
+	}
+	_, _ = io.WriteString(mw, "\n")
+
+	return e.block(ctx).Render(w, ctx)
+}
+
+// Finish finishes rendering a DetailsElement.
+func (e *DetailsElement) Finish(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+
+	// the body starts right below the summary
+	body := bytes.TrimLeft(bs.Current().Block.Bytes(), "\n")
+	body = append([]byte(nil), body...)
+	bs.Current().Block.Reset()
+	bs.Current().Block.Write(body)
+
+	var buf bytes.Buffer
+	if err := e.block(ctx).Finish(&buf, ctx); err != nil {
+		return err
+	}
+	out := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	if len(out) == 0 {
+		return nil
+	}
+	_, _ = w.Write(out)
+	if e.Expanded && ctx.folds.track {
+		_, _ = io.WriteString(w, foldMarker(e.Index, true))
+	}
+	_, err := io.WriteString(w, "\n")
+	return err //nolint: wrapcheck
+}
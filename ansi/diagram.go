@@ -0,0 +1,50 @@
+package ansi
+
+import (
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/glamour/internal/diagram"
+	"github.com/muesli/reflow/indent"
+)
+
+// A DiagramElement is used to render Mermaid diagrams as box-drawing art.
+// Diagrams that can't be parsed or don't fit the available width are
+// rendered as code blocks instead.
+type DiagramElement struct {
+	Code     string
+	Language string
+}
+
+// Render renders a DiagramElement.
+func (e *DiagramElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.CodeBlock
+
+	var indentation, margin uint
+	if rules.Indent != nil {
+		indentation = *rules.Indent
+	}
+	if rules.Margin != nil {
+		margin = *rules.Margin
+	}
+
+	width := int(bs.Width(ctx)) - int(indentation+margin) //nolint: gosec
+	lines, err := diagram.Render(e.Code, width)
+	if err != nil {
+		el := &CodeBlockElement{
+			Code:     e.Code,
+			Language: e.Language,
+		}
+		return el.Render(w, ctx)
+	}
+
+	iw := indent.NewWriterPipe(w, indentation+margin, func(_ io.Writer) {
+		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, " ")
+	})
+	el := &BaseElement{
+		Token: strings.Join(lines, "\n") + "\n",
+		Style: rules.StylePrimitive,
+	}
+	return el.Render(iw, ctx)
+}
@@ -0,0 +1,227 @@
+package ansi
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/quick"
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+type diffLineKind int
+
+const (
+	diffContext diffLineKind = iota
+	diffAdded
+	diffRemoved
+	diffHunk
+	diffHeader
+)
+
+var (
+	diffHunkRe = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+	diffHeaderPrefixes = []string{
+		"diff ", "index ", "--- ", "+++ ", "new file mode", "deleted file mode",
+		"old mode", "new mode", "similarity index", "rename from", "rename to",
+		"Binary files",
+	}
+)
+
+// isDiff returns whether a code block contains a unified diff, either
+// declared by its language or detected by its file and hunk headers.
+func isDiff(language, code string) bool {
+	switch strings.ToLower(language) {
+	case "diff", "patch", "udiff":
+		return true
+	case "":
+		return strings.HasPrefix(code, "--- ") && strings.Contains(code, "\n+++ ") &&
+			strings.Contains(code, "\n@@ -")
+	}
+	return false
+}
+
+// A diffLine is a classified line of a unified diff.
+type diffLine struct {
+	kind diffLineKind
+	text string
+}
+
+// parseDiff classifies the lines of a unified diff and returns the name of
+// the file it changes. Hunk headers are used to tell changed lines apart from
+// file headers, e.g. a removed line starting with "-- ".
+func parseDiff(code string) ([]diffLine, string) {
+	var lines []diffLine
+	var file, oldFile string
+	var oldLeft, newLeft int
+
+	for _, l := range strings.Split(strings.TrimSuffix(code, "\n"), "\n") {
+		kind := diffContext
+		switch {
+		case oldLeft > 0 || newLeft > 0:
+			switch {
+			case strings.HasPrefix(l, "+"):
+				kind = diffAdded
+				newLeft--
+			case strings.HasPrefix(l, "-"):
+				kind = diffRemoved
+				oldLeft--
+			case strings.HasPrefix(l, "\\"):
+				kind = diffHeader
+			default:
+				oldLeft--
+				newLeft--
+			}
+
+		case strings.HasPrefix(l, "@@"):
+			kind = diffHunk
+			if m := diffHunkRe.FindStringSubmatch(l); m != nil {
+				oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[2])
+			}
+
+		case hasDiffHeaderPrefix(l):
+			kind = diffHeader
+			if name, ok := strings.CutPrefix(l, "+++ "); ok {
+				file = diffFileName(name)
+			} else if name, ok := strings.CutPrefix(l, "--- "); ok {
+				oldFile = diffFileName(name)
+			}
+
+		case strings.HasPrefix(l, "+"):
+			kind = diffAdded
+		case strings.HasPrefix(l, "-"):
+			kind = diffRemoved
+		}
+		lines = append(lines, diffLine{kind: kind, text: l})
+	}
+
+	if file == "" || file == "/dev/null" {
+		file = oldFile
+	}
+	return lines, file
+}
+
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func hasDiffHeaderPrefix(l string) bool {
+	for _, p := range diffHeaderPrefixes {
+		if strings.HasPrefix(l, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffFileName returns the file name of a "---" or "+++" header, without
+// its "a/" or "b/" prefix and timestamp.
+func diffFileName(s string) string {
+	s, _, _ = strings.Cut(s, "\t")
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+// renderDiff renders a unified diff, coloring each line by its kind and
+// optionally syntax-highlighting the changed code. It returns the rendered
+// lines and each line's background.
+func renderDiff(ctx RenderContext, code, formatter, theme string) (string, []string) {
+	p := ctx.options.ColorProfile
+	rules := ctx.options.Styles.CodeBlock
+	base := ctx.blockStack.With(rules.StylePrimitive)
+
+	styles := map[diffLineKind]StylePrimitive{
+		diffContext: cascadeStylePrimitives(base, rules.Diff.Context),
+		diffAdded:   cascadeStylePrimitives(base, rules.Diff.Added),
+		diffRemoved: cascadeStylePrimitives(base, rules.Diff.Removed),
+		diffHunk:    cascadeStylePrimitives(base, rules.Diff.Hunk),
+		diffHeader:  cascadeStylePrimitives(base, rules.Diff.Header),
+	}
+	backgrounds := map[diffLineKind]string{
+		diffContext: backgroundSequence(p, rules.Diff.Context.BackgroundColor),
+		diffAdded:   backgroundSequence(p, rules.Diff.Added.BackgroundColor),
+		diffRemoved: backgroundSequence(p, rules.Diff.Removed.BackgroundColor),
+		diffHunk:    backgroundSequence(p, rules.Diff.Hunk.BackgroundColor),
+		diffHeader:  backgroundSequence(p, rules.Diff.Header.BackgroundColor),
+	}
+
+	lines, file := parseDiff(code)
+
+	var lexer chroma.Lexer
+	if theme != "" && rules.Diff.SyntaxHighlighting && file != "" {
+		lexer = lexers.Match(filepath.Base(file))
+	}
+	highlighted := make([]string, len(lines))
+	if lexer != nil {
+		highlightDiffCode(lines, highlighted, lexer.Config().Name, formatter, theme)
+	}
+
+	var b strings.Builder
+	bgs := make([]string, 0, len(lines))
+	for i, l := range lines {
+		st := styles[l.kind]
+		switch {
+		case l.kind >= diffHunk || l.text == "":
+			renderText(&b, p, st, l.text)
+		case highlighted[i] != "":
+			renderText(&b, p, st, l.text[:1])
+			b.WriteString(highlighted[i])
+		default:
+			renderText(&b, p, st, l.text[:1])
+			renderText(&b, p, st, l.text[1:])
+		}
+		b.WriteString("\n")
+		bgs = append(bgs, backgrounds[l.kind])
+	}
+	return b.String(), bgs
+}
+
+// highlightDiffCode syntax-highlights the code of each hunk, without the
+// leading markers, storing the result of each line in highlighted.
+func highlightDiffCode(lines []diffLine, highlighted []string, language, formatter, theme string) {
+	var start int
+	var code []string
+
+	flush := func(end int) {
+		defer func() { code = code[:0] }()
+		if len(code) == 0 {
+			return
+		}
+		var buf bytes.Buffer
+		if err := quick.Highlight(&buf, strings.Join(code, "\n")+"\n", language, formatter, theme); err != nil {
+			return
+		}
+		out := strings.Split(buf.String(), "\n")
+		// trailing escape sequences belong to the last line
+		for len(out) > len(code) && xansi.Strip(out[len(out)-1]) == "" {
+			out[len(out)-2] += out[len(out)-1]
+			out = out[:len(out)-1]
+		}
+		if len(out) != len(code) {
+			return
+		}
+		copy(highlighted[start:end], out)
+	}
+
+	for i, l := range lines {
+		if l.kind >= diffHunk || l.text == "" {
+			flush(i)
+			start = i + 1
+			continue
+		}
+		code = append(code, l.text[1:])
+	}
+	flush(len(lines))
+}
@@ -7,7 +7,16 @@ import (
 	"io"
 	"strings"
 
+	"github.com/charmbracelet/glamour/internal/alert"
 	"github.com/charmbracelet/glamour/internal/autolink"
+	"github.com/charmbracelet/glamour/internal/csvtable"
+	"github.com/charmbracelet/glamour/internal/details"
+	"github.com/charmbracelet/glamour/internal/fence"
+	"github.com/charmbracelet/glamour/internal/frontmatter"
+	"github.com/charmbracelet/glamour/internal/htmlinline"
+	"github.com/charmbracelet/glamour/internal/spans"
+	"github.com/charmbracelet/glamour/internal/texmath"
+	"github.com/charmbracelet/glamour/internal/toc"
 	east "github.com/yuin/goldmark-emoji/ast"
 	"github.com/yuin/goldmark/ast"
 	astext "github.com/yuin/goldmark/extension/ast"
@@ -35,6 +44,20 @@ type Element struct {
 	Exiting  string
 	Renderer ElementRenderer
 	Finisher ElementFinisher
+
+	// SkipChildren hides the node's children, e.g. the body of a collapsed
+	// <details> section.
+	SkipChildren bool
+}
+
+// isFirst reports whether node is the first rendered child of its parent.
+// Hidden front matter doesn't count as a previous sibling.
+func (tr *ANSIRenderer) isFirst(node ast.Node) bool {
+	prev := node.PreviousSibling()
+	if prev != nil && prev.Kind() == frontmatter.KindFrontMatter {
+		return !tr.context.options.FrontMatterHeader
+	}
+	return prev == nil
 }
 
 // NewElement returns the appropriate render Element for a given node.
@@ -59,7 +82,7 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 		n := node.(*ast.Heading)
 		he := &HeadingElement{
 			Level: n.Level,
-			First: node.PreviousSibling() == nil,
+			First: tr.isFirst(node),
 		}
 		return Element{
 			Exiting:  "",
@@ -77,7 +100,7 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 		}
 		return Element{
 			Renderer: &ParagraphElement{
-				First: node.PreviousSibling() == nil,
+				First: tr.isFirst(node),
 			},
 			Finisher: &ParagraphElement{},
 		}
@@ -95,6 +118,39 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			Finisher: e,
 		}
 
+	// Alerts
+	case alert.KindAlert:
+		n := node.(*alert.Alert)
+		e := &AlertElement{
+			Type:  n.AlertType,
+			Title: n.Title,
+		}
+		return Element{
+			Entering: "\n",
+			Renderer: e,
+			Finisher: e,
+		}
+
+	// Details
+	case details.KindDetails:
+		n := node.(*details.Details)
+		expanded := n.Open || !ctx.options.CollapseDetails
+		if v, ok := ctx.folds.expanded[n.Key()]; ok {
+			expanded = v
+		}
+		e := &DetailsElement{
+			Index:    n.Index,
+			ID:       n.ID,
+			Summary:  n.Summary,
+			Expanded: expanded,
+			First:    tr.isFirst(node),
+		}
+		return Element{
+			Renderer:     e,
+			Finisher:     e,
+			SkipChildren: !expanded,
+		}
+
 	// Lists
 	case ast.KindList:
 		s := ctx.options.Styles.List.StyleBlock
@@ -182,6 +238,15 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			},
 		}
 
+	case ast.KindString:
+		n := node.(*ast.String)
+		return Element{
+			Renderer: &BaseElement{
+				Token: string(n.Value),
+				Style: ctx.options.Styles.Text,
+			},
+		}
+
 	case ast.KindEmphasis:
 		n := node.(*ast.Emphasis)
 		var children []ElementRenderer
@@ -336,11 +401,43 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			line := n.Lines().At(i)
 			s += string(line.Value(source))
 		}
+		info := fence.Parse("")
+		if n.Info != nil {
+			info = fence.Parse(string(n.Info.Segment.Value(source)))
+		}
+		if info.Language == "mermaid" {
+			return Element{
+				Entering: "\n",
+				Renderer: &DiagramElement{
+					Code:     s,
+					Language: info.Language,
+				},
+			}
+		}
+		if comma, ok := csvSeparators[info.Language]; ok && info.Attributes["table"] != "false" {
+			header := csvtable.HeaderAuto
+			switch info.Attributes["header"] {
+			case "true":
+				header = csvtable.HeaderOn
+			case "false":
+				header = csvtable.HeaderOff
+			}
+			return Element{
+				Entering: "\n",
+				Renderer: &CSVTableElement{
+					Code:     s,
+					Language: info.Language,
+					Comma:    comma,
+					Header:   header,
+				},
+			}
+		}
 		return Element{
 			Entering: "\n",
 			Renderer: &CodeBlockElement{
 				Code:     s,
-				Language: string(n.Language(source)),
+				Language: info.Language,
+				Info:     info,
 			},
 		}
 
@@ -369,6 +466,33 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			},
 		}
 
+	// Math
+	case texmath.KindInlineMath:
+		n := node.(*texmath.InlineMath)
+		return Element{
+			Renderer: &BaseElement{
+				Token: texmath.Inline(n.Formula),
+				Style: ctx.options.Styles.Math.Inline,
+			},
+		}
+
+	case texmath.KindDisplayMath:
+		n := node.(*texmath.DisplayMath)
+		e := &MathElement{
+			Block: &BlockElement{
+				Block:   &bytes.Buffer{},
+				Style:   cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.Math.StyleBlock, false),
+				Margin:  true,
+				Newline: true,
+			},
+			Formula: n.Formula(source),
+		}
+		return Element{
+			Entering: "\n",
+			Renderer: e,
+			Finisher: e,
+		}
+
 	// Tables
 	case astext.KindTable:
 		table := node.(*astext.Table)
@@ -412,10 +536,53 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 	// HTML Elements
 	case ast.KindHTMLBlock:
 		n := node.(*ast.HTMLBlock)
+		var b bytes.Buffer
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			b.Write(line.Value(source))
+		}
+		if n.HasClosure() {
+			b.Write(n.ClosureLine.Value(source))
+		}
 		return Element{
-			Renderer: &BaseElement{
-				Token: ctx.SanitizeHTML(string(n.Text(source)), true), //nolint: staticcheck
-				Style: ctx.options.Styles.HTMLBlock.StylePrimitive,
+			Renderer: &HTMLBlockElement{
+				HTML:  b.String(),
+				First: tr.isFirst(node),
+				// the summary of a <details> section is rendered by it
+				SkipSummary: node.Parent().Kind() == details.KindDetails && node.PreviousSibling() == nil,
+			},
+		}
+	case spans.KindSpan:
+		n := node.(*spans.Span)
+		var children []ElementRenderer
+		for nn := n.FirstChild(); nn != nil; nn = nn.NextSibling() {
+			children = append(children, tr.NewElement(nn, source).Renderer)
+		}
+		e := &SpanElement{
+			Style:    spanStyle(ctx, n.SpanType),
+			Children: children,
+		}
+		if n.SpanType == spans.Sub || n.SpanType == spans.Sup {
+			text, _ := nodeContent(n, source)
+			e.Script = n.SpanType
+			e.Text = string(text)
+		}
+		return Element{
+			Renderer: e,
+		}
+	case htmlinline.KindTag:
+		n := node.(*htmlinline.Tag)
+		var children []ElementRenderer
+		for nn := n.FirstChild(); nn != nil; nn = nn.NextSibling() {
+			children = append(children, tr.NewElement(nn, source).Renderer)
+		}
+		text, _ := nodeContent(n, source)
+		return Element{
+			Renderer: &HTMLTagElement{
+				Name:      n.Name,
+				Text:      string(text),
+				Children:  children,
+				LineBreak: htmlLineBreak(n),
 			},
 		}
 	case ast.KindRawHTML:
@@ -456,6 +623,43 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			},
 		}
 
+	// Front Matter
+	case frontmatter.KindFrontMatter:
+		if !ctx.options.FrontMatterHeader {
+			return Element{}
+		}
+		e := &FrontMatterElement{
+			Block: &BlockElement{
+				Block:   &bytes.Buffer{},
+				Style:   cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.FrontMatter.StyleBlock, false),
+				Margin:  true,
+				Newline: true,
+			},
+			Values: node.(*frontmatter.FrontMatter).Values,
+		}
+		return Element{
+			Renderer: e,
+			Finisher: e,
+		}
+
+	// Table of Contents
+	case toc.KindTableOfContents:
+		e := &TOCElement{
+			Block: &BlockElement{
+				Block:   &bytes.Buffer{},
+				Style:   cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.TOC.StyleBlock, false),
+				Margin:  true,
+				Newline: true,
+			},
+			node:   node,
+			source: source,
+		}
+		return Element{
+			Entering: "\n",
+			Renderer: e,
+			Finisher: e,
+		}
+
 	// Handled by parents
 	case astext.KindTaskCheckBox:
 		// handled by KindListItem
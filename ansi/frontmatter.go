@@ -0,0 +1,134 @@
+package ansi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A FrontMatterElement is used to render a document's front matter as a
+// metadata header.
+type FrontMatterElement struct {
+	Block  *BlockElement
+	Values map[string]interface{}
+}
+
+// frontMatterString returns the front matter value of key as a string. Keys
+// of nested values are joined with dots, as in "author.name". Values that
+// are neither scalars nor lists of scalars are skipped.
+func frontMatterString(values map[string]interface{}, key string) string {
+	var v interface{} = values
+	for _, k := range strings.Split(key, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[k]
+	}
+	if s, ok := v.([]interface{}); ok {
+		parts := make([]string, 0, len(s))
+		for _, p := range s {
+			if str, ok := scalarString(p); ok {
+				parts = append(parts, str)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	str, _ := scalarString(v)
+	return str
+}
+
+// scalarString formats a scalar front matter value, reporting false for
+// empty values, maps and lists.
+func scalarString(v interface{}) (string, bool) {
+	switch v.(type) {
+	case nil, map[string]interface{}, []interface{}:
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// frontMatterTags returns the front matter's tags, which may be given as a
+// list or as a comma separated string.
+func frontMatterTags(values map[string]interface{}) []string {
+	var tags []string
+	switch v := values["tags"].(type) {
+	case []interface{}:
+		for _, t := range v {
+			if tag, ok := scalarString(t); ok {
+				tags = append(tags, tag)
+			}
+		}
+	case string:
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags
+}
+
+// Render renders a FrontMatterElement.
+func (e *FrontMatterElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.FrontMatter
+
+	if err := e.Block.Render(w, ctx); err != nil {
+		return err
+	}
+
+	var lines [][]*BaseElement
+	if title := frontMatterString(e.Values, "title"); title != "" {
+		lines = append(lines, []*BaseElement{{Token: title, Style: rules.Title}})
+	}
+
+	var byline []*BaseElement
+	author := frontMatterString(e.Values, "author")
+	if author == "" {
+		author = frontMatterString(e.Values, "author.name")
+	}
+	if author != "" {
+		byline = append(byline, &BaseElement{Token: author, Style: rules.Author})
+	}
+	if date := frontMatterString(e.Values, "date"); date != "" {
+		el := &BaseElement{Token: date, Style: rules.Date}
+		if len(byline) > 0 {
+			el.Prefix = rules.Separator
+		}
+		byline = append(byline, el)
+	}
+	if len(byline) > 0 {
+		lines = append(lines, byline)
+	}
+
+	var tags []*BaseElement
+	for i, tag := range frontMatterTags(e.Values) {
+		el := &BaseElement{Token: tag, Style: rules.Tag}
+		if i > 0 {
+			el.Prefix = " "
+		}
+		tags = append(tags, el)
+	}
+	if len(tags) > 0 {
+		lines = append(lines, tags)
+	}
+
+	b := bs.Current().Block
+	for i, line := range lines {
+		if i > 0 {
+			_, _ = io.WriteString(b, "\n")
+		}
+		for _, el := range line {
+			if err := el.Render(b, ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Finish finishes rendering a FrontMatterElement.
+func (e *FrontMatterElement) Finish(w io.Writer, ctx RenderContext) error {
+	return e.Block.Finish(w, ctx)
+}
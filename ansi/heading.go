@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/muesli/reflow/wordwrap"
 )
@@ -54,8 +55,49 @@ func (e *HeadingElement) Render(w io.Writer, ctx RenderContext) error {
 	}
 	bs.Push(be)
 
+	ctx.headings.current = ""
+	if n := ctx.options.HeadingNumbering; n != nil {
+		ctx.headings.current = n.Format(e.Level, ctx.headings.Next(e.Level))
+	}
+
 	renderText(w, ctx.options.ColorProfile, bs.Parent().Style.StylePrimitive, rules.BlockPrefix)
-	renderText(bs.Current().Block, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.Prefix)
+	if rules.Format != "" {
+		// prefix and number get rendered around the formatted heading text
+		return nil
+	}
+
+	renderText(bs.Current().Block, ctx.options.ColorProfile, prefixStyle(bs.Current().Style.StylePrimitive), rules.Prefix)
+	if ctx.headings.current != "" {
+		renderText(bs.Current().Block, ctx.options.ColorProfile, prefixStyle(bs.Current().Style.StylePrimitive), ctx.headings.current+" ")
+	}
+	return nil
+}
+
+// formatHeading applies the heading's format to the rendered heading text,
+// like BaseElement applies formats to tokens, giving the template access to
+// the text and the heading's section number. The format's own text is
+// rendered in the heading's style, while the heading text keeps the styles of
+// its inline elements.
+func formatHeading(ctx RenderContext, rules StyleBlock, block *bytes.Buffer) error {
+	p := ctx.options.ColorProfile
+	prefix, suffix := newSGRStyle(p, rules.StylePrimitive).wrapper()
+
+	// the heading's style applies again after each inline element's reset
+	text := block.String()
+	if prefix != "" {
+		text = strings.ReplaceAll(text, suffix, suffix+prefix)
+	}
+	s, err := formatToken(rules.Format, text, map[string]interface{}{
+		"number": ctx.headings.current,
+		"meta":   ctx.meta,
+	})
+	if err != nil {
+		return err
+	}
+
+	block.Reset()
+	renderText(block, p, prefixStyle(rules.StylePrimitive), rules.Prefix)
+	_, _ = io.WriteString(block, prefix+s+suffix)
 	return nil
 }
 
@@ -64,6 +106,12 @@ func (e *HeadingElement) Finish(w io.Writer, ctx RenderContext) error {
 	bs := ctx.blockStack
 	rules := bs.Current().Style
 
+	if rules.Format != "" {
+		if err := formatHeading(ctx, rules, bs.Current().Block); err != nil {
+			return err
+		}
+	}
+
 	// Check if Kitty text sizing is enabled and this heading style has scale set
 	if IsKittyTextSizingEnabled() && hasKittyTextSizing(rules.StylePrimitive) {
 		// Extract plain text from the block buffer (strip any per-token ANSI codes)
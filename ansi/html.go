@@ -0,0 +1,434 @@
+package ansi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/yuin/goldmark/ast"
+	astext "github.com/yuin/goldmark/extension/ast"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// lineBreak marks a line break, e.g. from a <br> tag, that is kept when a
+// paragraph gets word-wrapped.
+const lineBreak = "\u2028"
+
+// htmlTagStyle returns the style of an HTML element, reporting false for
+// elements rendered as plain text.
+func htmlTagStyle(ctx RenderContext, name string) (StylePrimitive, bool) {
+	styles := ctx.options.Styles
+	switch name {
+	case "b", "strong":
+		return styles.Strong, true
+	case "i", "em", "cite", "var":
+		return styles.Emph, true
+	case "s", "del", "strike":
+		return styles.Strikethrough, true
+	case "code", "samp":
+		return styles.Code.StylePrimitive, true
+	case "kbd":
+		return styles.Kbd, true
+	case "mark":
+		return styles.Mark, true
+	case "ins":
+		return styles.Ins, true
+	case "u":
+		return styles.Annotation, true
+	case "sub":
+		return styles.Sub, true
+	case "sup":
+		return styles.Sup, true
+	case "summary":
+		return styles.Summary, true
+	}
+	return StylePrimitive{}, false
+}
+
+// htmlLineBreak returns what a <br> element renders as: a line break marker
+// within paragraphs, which get word-wrapped, a space within headings and a
+// newline elsewhere.
+func htmlLineBreak(node ast.Node) string {
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		switch n.Kind() { //nolint: exhaustive
+		case ast.KindParagraph:
+			return lineBreak
+		case ast.KindHeading, astext.KindTableCell:
+			return " "
+		}
+	}
+	return "\n"
+}
+
+// An HTMLTagElement is used to render inline HTML elements.
+type HTMLTagElement struct {
+	Name     string
+	Text     string
+	Children []ElementRenderer
+
+	// LineBreak is what a <br> element renders as.
+	LineBreak string
+}
+
+// Render renders an HTMLTagElement.
+func (e *HTMLTagElement) Render(w io.Writer, ctx RenderContext) error {
+	return e.StyleOverrideRender(w, ctx, StylePrimitive{})
+}
+
+// StyleOverrideRender renders an HTMLTagElement with a given style.
+func (e *HTMLTagElement) StyleOverrideRender(w io.Writer, ctx RenderContext, style StylePrimitive) error {
+	if e.Name == "br" {
+		_, err := io.WriteString(w, e.LineBreak)
+		return err //nolint: wrapcheck
+	}
+
+	rules, _ := htmlTagStyle(ctx, e.Name)
+	span := &SpanElement{
+		Style:    rules,
+		Children: e.Children,
+		Text:     e.Text,
+	}
+	if e.Name == "sub" || e.Name == "sup" {
+		span.Script = e.Name
+	}
+	return span.StyleOverrideRender(w, ctx, style)
+}
+
+// An HTMLBlockElement is used to render HTML blocks. Known elements are
+// styled, images and links are shown with their URLs, and <summary>
+// elements start a line of their own.
+type HTMLBlockElement struct {
+	HTML  string
+	First bool
+
+	// SkipSummary hides the first <summary> element.
+	SkipSummary bool
+}
+
+// renderHTML renders the text of an HTML fragment, styling known elements.
+func renderHTML(ctx RenderContext, src string, style StylePrimitive, skipSummary bool) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("glamour: error parsing HTML: %w", err)
+	}
+
+	hw := &htmlWriter{ctx: ctx, skipSummary: skipSummary}
+	for _, n := range nodes {
+		if err := hw.node(n, style); err != nil {
+			return "", err
+		}
+	}
+	return strings.Trim(hw.b.String(), "\n"), nil
+}
+
+// Render renders an HTMLBlockElement.
+func (e *HTMLBlockElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+
+	text, err := renderHTML(ctx, e.HTML, ctx.options.Styles.HTMLBlock.StylePrimitive, e.SkipSummary)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(xansi.Strip(text)) == "" {
+		return nil
+	}
+
+	if !e.First {
+		_, _ = io.WriteString(w, "\n")
+	}
+	width := int(bs.Width(ctx)) //nolint: gosec
+	mw := NewMarginWriter(ctx, w, cascadeStyle(bs.Current().Style, ctx.options.Styles.HTMLBlock, false))
+	for _, line := range strings.Split(text, "\n") {
+		if _, err := io.WriteString(mw, wordwrap.String(line, width)+"\n"); err != nil {
+			return err //nolint: wrapcheck
+		}
+	}
+	return nil
+}
+
+// htmlWriter writes the text of HTML nodes, styling known elements.
+type htmlWriter struct {
+	ctx RenderContext
+	b   bytes.Buffer
+
+	// space is set when whitespace precedes the next text.
+	space bool
+
+	// skipSummary is set while the next <summary> element is to be hidden.
+	skipSummary bool
+
+	// pre is set within <pre> elements, whose whitespace is kept.
+	pre bool
+
+	// lists holds the enclosing <ul> and <ol> elements, innermost last.
+	lists []*htmlList
+}
+
+// htmlList is a <ul> or <ol> element, numbering its items from next.
+type htmlList struct {
+	ordered bool
+	next    uint
+}
+
+// newline ends the current line unless it's empty.
+func (hw *htmlWriter) newline() {
+	if hw.b.Len() > 0 && !bytes.HasSuffix(hw.b.Bytes(), []byte("\n")) {
+		hw.b.WriteString("\n")
+	}
+	hw.space = false
+}
+
+// writeSpace writes the whitespace preceding the next text, unless it
+// starts a line.
+func (hw *htmlWriter) writeSpace() {
+	if hw.space && hw.b.Len() > 0 && !bytes.HasSuffix(hw.b.Bytes(), []byte("\n")) {
+		hw.b.WriteString(" ")
+	}
+	hw.space = false
+}
+
+func (hw *htmlWriter) text(s string, style StylePrimitive) error {
+	if hw.pre {
+		return hw.preText(s, style)
+	}
+
+	const whitespace = " \t\r\n"
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		hw.space = hw.space || s != ""
+		return nil
+	}
+	hw.space = hw.space || strings.TrimLeft(s, whitespace) != s
+	hw.writeSpace()
+	hw.space = strings.TrimRight(s, whitespace) != s
+
+	el := &BaseElement{
+		Token: strings.Join(words, " "),
+		Style: style,
+	}
+	return el.Render(&hw.b, hw.ctx)
+}
+
+// preText writes the text of a <pre> element line by line, keeping its
+// whitespace.
+func (hw *htmlWriter) preText(s string, style StylePrimitive) error {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			hw.b.WriteString("\n")
+		}
+		if line == "" {
+			continue
+		}
+		el := &BaseElement{
+			Token: line,
+			Style: style,
+		}
+		if err := el.Render(&hw.b, hw.ctx); err != nil {
+			return err
+		}
+	}
+	hw.space = false
+	return nil
+}
+
+// list writes the items of a <ul> or <ol> element.
+func (hw *htmlWriter) list(n *html.Node, style StylePrimitive) error {
+	l := &htmlList{ordered: n.DataAtom == atom.Ol, next: 1}
+	if start, err := strconv.ParseUint(htmlAttr(n, "start"), 10, 32); err == nil {
+		l.next = uint(start)
+	}
+
+	hw.newline()
+	hw.lists = append(hw.lists, l)
+	err := hw.children(n, style)
+	hw.lists = hw.lists[:len(hw.lists)-1]
+	hw.newline()
+	return err
+}
+
+// item writes a list item, prefixed like the items of Markdown lists and
+// indented by its list's nesting level.
+func (hw *htmlWriter) item(n *html.Node, style StylePrimitive) error {
+	l := hw.lists[len(hw.lists)-1]
+
+	hw.newline()
+	indent := int(hw.ctx.options.Styles.List.LevelIndent) * (len(hw.lists) - 1) //nolint: gosec
+	hw.b.WriteString(strings.Repeat(" ", indent))
+	el := &ItemElement{
+		IsOrdered:   l.ordered,
+		Enumeration: l.next,
+	}
+	l.next++
+	if err := el.Render(&hw.b, hw.ctx); err != nil {
+		return err
+	}
+	if err := hw.children(n, style); err != nil {
+		return err
+	}
+	hw.newline()
+	return nil
+}
+
+func (hw *htmlWriter) children(n *html.Node, style StylePrimitive) error {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := hw.node(c, style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (hw *htmlWriter) node(n *html.Node, style StylePrimitive) error {
+	switch n.Type { //nolint: exhaustive
+	case html.TextNode:
+		return hw.text(n.Data, style)
+	case html.ElementNode:
+	default:
+		return nil
+	}
+
+	p := hw.ctx.options.ColorProfile
+	switch n.DataAtom { //nolint: exhaustive
+	case atom.Script, atom.Style, atom.Head, atom.Title, atom.Template:
+		return nil
+
+	case atom.Summary:
+		if hw.skipSummary {
+			hw.skipSummary = false
+			return nil
+		}
+
+	case atom.Br:
+		hw.b.WriteString("\n")
+		hw.space = false
+		return nil
+
+	case atom.Img:
+		hw.writeSpace()
+		el := &ImageElement{
+			Text:    htmlAttr(n, "alt"),
+			BaseURL: hw.ctx.options.BaseURL,
+			URL:     htmlAttr(n, "src"),
+		}
+		hw.space = true
+		return el.Render(&hw.b, hw.ctx)
+
+	case atom.A:
+		if err := hw.children(n, cascadeStylePrimitives(style, hw.ctx.options.Styles.LinkText)); err != nil {
+			return err
+		}
+		if href := htmlAttr(n, "href"); href != "" && !strings.HasPrefix(href, "#") {
+			el := &BaseElement{
+				Token:  resolveRelativeURL(hw.ctx.options.BaseURL, href),
+				Prefix: " ",
+				Style:  hw.ctx.options.Styles.Link,
+			}
+			hw.space = false
+			return el.Render(&hw.b, hw.ctx)
+		}
+		return nil
+
+	case atom.Sub, atom.Sup:
+		if s, ok := scriptChars(n.Data, htmlText(n)); ok {
+			return hw.text(s, style)
+		}
+
+	case atom.Ul, atom.Ol:
+		return hw.list(n, style)
+
+	case atom.Li:
+		if len(hw.lists) > 0 {
+			return hw.item(n, style)
+		}
+
+	case atom.Pre:
+		hw.newline()
+		hw.pre = true
+		err := hw.children(n, style)
+		hw.pre = false
+		hw.newline()
+		return err
+	}
+
+	block := htmlBlockElements[n.DataAtom]
+	if block {
+		hw.newline()
+	}
+	rules, ok := htmlTagStyle(hw.ctx, n.Data)
+	if ok {
+		st := cascadeStylePrimitives(style, rules)
+		if rules.Prefix != "" {
+			hw.writeSpace()
+		}
+		renderText(&hw.b, p, st, rules.Prefix)
+		st.Prefix, st.Suffix = "", ""
+		if err := hw.children(n, st); err != nil {
+			return err
+		}
+		renderText(&hw.b, p, cascadeStylePrimitives(style, rules), rules.Suffix)
+	} else if err := hw.children(n, style); err != nil {
+		return err
+	}
+	if block {
+		hw.newline()
+	}
+	return nil
+}
+
+// htmlBlockElements holds the elements rendered on lines of their own.
+var htmlBlockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Center: true, atom.Section: true,
+	atom.Header: true, atom.Footer: true, atom.Article: true, atom.Aside: true,
+	atom.Nav: true, atom.Figure: true, atom.Figcaption: true, atom.Blockquote: true,
+	atom.Details: true, atom.Summary: true, atom.Ul: true, atom.Ol: true,
+	atom.Li: true, atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Pre: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true,
+	atom.H6: true, atom.Hr: true, atom.Table: true, atom.Tr: true,
+	atom.Picture: true,
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// htmlPlainText returns the text of an HTML fragment without its markup.
+func htmlPlainText(src string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return src
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(htmlText(n))
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func htmlText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(htmlText(c))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -86,40 +86,7 @@ func buildKittyMetadata(rules StylePrimitive) string {
 // applyANSIStyles applies standard ANSI styling (colors, bold, italic, etc.) to the text
 // and returns the styled string.
 func applyANSIStyles(p termenv.Profile, rules StylePrimitive, s string) string {
-	out := termenv.String(s)
-
-	if rules.Color != nil {
-		out = out.Foreground(p.Color(*rules.Color))
-	}
-	if rules.BackgroundColor != nil {
-		out = out.Background(p.Color(*rules.BackgroundColor))
-	}
-	if rules.Underline != nil && *rules.Underline {
-		out = out.Underline()
-	}
-	if rules.Bold != nil && *rules.Bold {
-		out = out.Bold()
-	}
-	if rules.Italic != nil && *rules.Italic {
-		out = out.Italic()
-	}
-	if rules.CrossedOut != nil && *rules.CrossedOut {
-		out = out.CrossOut()
-	}
-	if rules.Overlined != nil && *rules.Overlined {
-		out = out.Overline()
-	}
-	if rules.Inverse != nil && *rules.Inverse {
-		out = out.Reverse()
-	}
-	if rules.Blink != nil && *rules.Blink {
-		out = out.Blink()
-	}
-	if rules.Faint != nil && *rules.Faint {
-		out = out.Faint()
-	}
-
-	return out.String()
+	return newSGRStyle(p, rules).styled(s)
 }
 
 // renderKittyScaledText renders text using Kitty's OSC 66 text sizing protocol.
@@ -182,54 +149,7 @@ func StripANSI(s string) string {
 // buildANSIWrapper returns the ANSI escape sequence prefix and reset suffix
 // for applying styles around content.
 func buildANSIWrapper(p termenv.Profile, rules StylePrimitive) (prefix, suffix string) {
-	// Build a styled empty string to extract the escape codes
-	out := termenv.String("")
-
-	if rules.Color != nil {
-		out = out.Foreground(p.Color(*rules.Color))
-	}
-	if rules.BackgroundColor != nil {
-		out = out.Background(p.Color(*rules.BackgroundColor))
-	}
-	if rules.Underline != nil && *rules.Underline {
-		out = out.Underline()
-	}
-	if rules.Bold != nil && *rules.Bold {
-		out = out.Bold()
-	}
-	if rules.Italic != nil && *rules.Italic {
-		out = out.Italic()
-	}
-	if rules.CrossedOut != nil && *rules.CrossedOut {
-		out = out.CrossOut()
-	}
-	if rules.Overlined != nil && *rules.Overlined {
-		out = out.Overline()
-	}
-	if rules.Inverse != nil && *rules.Inverse {
-		out = out.Reverse()
-	}
-	if rules.Blink != nil && *rules.Blink {
-		out = out.Blink()
-	}
-	if rules.Faint != nil && *rules.Faint {
-		out = out.Faint()
-	}
-
-	styled := out.String()
-	// The styled string is just escape codes around empty content
-	// Format is typically: \x1b[...m\x1b[0m
-	// We want the opening codes as prefix and reset as suffix
-	if len(styled) > 0 {
-		// Find the reset sequence position
-		resetSeq := "\x1b[0m"
-		if idx := strings.Index(styled, resetSeq); idx >= 0 {
-			prefix = styled[:idx]
-			suffix = resetSeq
-		}
-	}
-
-	return prefix, suffix
+	return newSGRStyle(p, rules).wrapper()
 }
 
 // GetKittyScaleRows returns the number of additional rows that scaled text will occupy.
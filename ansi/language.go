@@ -0,0 +1,53 @@
+package ansi
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour/internal/langdetect"
+)
+
+// DefaultLanguageAliases maps common language aliases to the names of Chroma
+// lexers.
+var DefaultLanguageAliases = map[string]string{
+	"sh":     "bash",
+	"shell":  "bash",
+	"zsh":    "bash",
+	"yml":    "yaml",
+	"tf":     "hcl",
+	"jsonc":  "json",
+	"golang": "go",
+}
+
+// LanguageDetection configures how the language of code blocks is
+// determined. Declared languages are resolved through aliases, and the
+// language of code blocks without one is guessed from shebang lines, simple
+// heuristics and Chroma's lexer analysis.
+type LanguageDetection struct {
+	// Threshold is the minimum confidence, between 0 and 1, a guessed
+	// language needs to be used for highlighting.
+	Threshold float32
+
+	// Aliases maps language names to the names of Chroma lexers. They take
+	// precedence over DefaultLanguageAliases.
+	Aliases map[string]string
+}
+
+// language returns the language a code block gets highlighted in.
+func (d *LanguageDetection) language(declared, code string) string {
+	if declared == "" {
+		lang, confidence := langdetect.Detect(code)
+		if lang == "" || confidence < d.Threshold {
+			return ""
+		}
+		declared = lang
+	}
+
+	key := strings.ToLower(declared)
+	if alias, ok := d.Aliases[key]; ok {
+		return alias
+	}
+	if alias, ok := DefaultLanguageAliases[key]; ok {
+		return alias
+	}
+	return declared
+}
@@ -9,15 +9,22 @@ import (
 type ItemElement struct {
 	IsOrdered   bool
 	Enumeration uint
+
+	// Label replaces the enumeration of an ordered item, e.g. "1.2".
+	Label string
 }
 
 // Render renders an ItemElement.
 func (e *ItemElement) Render(w io.Writer, ctx RenderContext) error {
 	var el *BaseElement
 	if e.IsOrdered {
+		prefix := e.Label
+		if prefix == "" {
+			prefix = strconv.FormatInt(int64(e.Enumeration), 10) //nolint: gosec
+		}
 		el = &BaseElement{
 			Style:  ctx.options.Styles.Enumeration,
-			Prefix: strconv.FormatInt(int64(e.Enumeration), 10), //nolint: gosec
+			Prefix: prefix,
 		}
 	} else {
 		el = &BaseElement{
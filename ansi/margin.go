@@ -18,6 +18,12 @@ type MarginWriter struct {
 
 // NewMarginWriter returns a new MarginWriter.
 func NewMarginWriter(ctx RenderContext, w io.Writer, rules StyleBlock) *MarginWriter {
+	return newMarginWriter(ctx, w, rules, ctx.blockStack.Parent().Style.StylePrimitive)
+}
+
+// newMarginWriter returns a new MarginWriter that renders its indent token in
+// indentStyle.
+func newMarginWriter(ctx RenderContext, w io.Writer, rules StyleBlock, indentStyle StylePrimitive) *MarginWriter {
 	bs := ctx.blockStack
 
 	var indentation uint
@@ -38,7 +44,7 @@ func NewMarginWriter(ctx RenderContext, w io.Writer, rules StyleBlock) *MarginWr
 		ic = *rules.IndentToken
 	}
 	iw := indent.NewWriterPipe(pw, indentation+margin, func(_ io.Writer) {
-		renderText(w, ctx.options.ColorProfile, bs.Parent().Style.StylePrimitive, ic)
+		renderText(w, ctx.options.ColorProfile, indentStyle, ic)
 	})
 
 	return &MarginWriter{
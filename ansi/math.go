@@ -0,0 +1,53 @@
+package ansi
+
+import (
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/glamour/internal/texmath"
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+// A MathElement is used to render display math, centered within the
+// available width.
+type MathElement struct {
+	Block   *BlockElement
+	Formula string
+}
+
+// Render renders a MathElement.
+func (e *MathElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+
+	if err := e.Block.Render(w, ctx); err != nil {
+		return err
+	}
+
+	lines := texmath.Display(e.Formula)
+	width := 0
+	for _, l := range lines {
+		width = max(width, xansi.StringWidth(l))
+	}
+
+	available := int(bs.Width(ctx)) //nolint: gosec
+	if width > available {
+		// too wide to lay out, fall back to a single line that can be wrapped
+		lines = []string{texmath.Inline(e.Formula)}
+		width = 0
+	}
+
+	indent := strings.Repeat(" ", max(available-width, 0)/2) //nolint: mnd
+	b := bs.Current().Block
+	for i, l := range lines {
+		if i > 0 {
+			_, _ = io.WriteString(b, "\n")
+		}
+		renderText(b, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, indent+l)
+	}
+	return nil
+}
+
+// Finish finishes rendering a MathElement.
+func (e *MathElement) Finish(w io.Writer, ctx RenderContext) error {
+	return e.Block.Finish(w, ctx)
+}
@@ -0,0 +1,89 @@
+package ansi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HeadingNumbering configures automatic hierarchical heading numbers.
+type HeadingNumbering struct {
+	// StartLevel is the shallowest heading level that gets a number. Headings
+	// above it, e.g. a document title, are left unnumbered. Defaults to 1.
+	StartLevel int
+
+	// Formats maps a heading level to a fmt format string, which receives the
+	// section numbers from StartLevel down to that level, e.g. "%d)" or
+	// "%d.%d". Levels without a format join the numbers with dots, ending
+	// top-level numbers with a dot: "1.", "1.2", "1.2.3".
+	Formats map[int]string
+}
+
+// Format returns the formatted section number for a heading of the given
+// level.
+func (n HeadingNumbering) Format(level int, numbers []int) string {
+	if len(numbers) == 0 {
+		return ""
+	}
+
+	format, ok := n.Formats[level]
+	if !ok {
+		if len(numbers) == 1 {
+			return strconv.Itoa(numbers[0]) + "."
+		}
+		return joinNumbers(numbers)
+	}
+
+	// only pass as many numbers as the format has verbs
+	verbs := strings.Count(format, "%") - 2*strings.Count(format, "%%")
+	args := make([]interface{}, 0, len(numbers))
+	for i := len(numbers) - min(verbs, len(numbers)); i < len(numbers); i++ {
+		args = append(args, numbers[i])
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// headingCounter keeps track of hierarchical section numbers while walking a
+// document's headings in order.
+type headingCounter struct {
+	// StartLevel is the shallowest heading level that gets a number.
+	StartLevel int
+
+	counters []int
+	current  string
+}
+
+// Reset starts counting from scratch, e.g. for a new document.
+func (c *headingCounter) Reset(startLevel int) {
+	c.StartLevel = startLevel
+	c.counters = nil
+	c.current = ""
+}
+
+// Next advances the counter for a heading of the given level and returns the
+// section numbers from StartLevel down to level. It returns nil for headings
+// above StartLevel.
+func (c *headingCounter) Next(level int) []int {
+	start := max(c.StartLevel, 1)
+	if level < start {
+		return nil
+	}
+
+	depth := level - start + 1
+	for len(c.counters) < depth {
+		c.counters = append(c.counters, 0)
+	}
+	c.counters = c.counters[:depth]
+	c.counters[depth-1]++
+
+	return append([]int(nil), c.counters...)
+}
+
+// joinNumbers joins section numbers with dots, e.g. "1.2.3".
+func joinNumbers(numbers []int) string {
+	s := make([]string, len(numbers))
+	for i, n := range numbers {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ".")
+}
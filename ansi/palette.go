@@ -0,0 +1,105 @@
+package ansi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// paletteRefPrefix marks a color as a reference to a palette color, as in
+// "$accent".
+const paletteRefPrefix = "$"
+
+// ResolvePalette returns s with every palette reference, such as "$accent",
+// replaced by the palette color it names. Colors in palette take precedence
+// over the style's own palette, so a palette can recolor a style without
+// changing it otherwise.
+func ResolvePalette(s StyleConfig, palette map[string]string) (StyleConfig, error) {
+	colors := make(map[string]string, len(s.Palette)+len(palette))
+	for name, c := range s.Palette {
+		colors[name] = c
+	}
+	for name, c := range palette {
+		colors[name] = c
+	}
+
+	// walk the style's JSON representation, which reaches every color of
+	// every element without listing them
+	b, err := json.Marshal(s)
+	if err != nil {
+		return s, fmt.Errorf("glamour: error encoding style: %w", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return s, fmt.Errorf("glamour: error encoding style: %w", err)
+	}
+	delete(m, "palette")
+	if err := resolveColors(m, colors); err != nil {
+		return s, err
+	}
+
+	b, err = json.Marshal(m)
+	if err != nil {
+		return s, fmt.Errorf("glamour: error encoding style: %w", err)
+	}
+	var r StyleConfig
+	if err := json.Unmarshal(b, &r); err != nil {
+		return s, fmt.Errorf("glamour: error parsing style: %w", err)
+	}
+	if len(colors) > 0 {
+		r.Palette = colors
+	}
+	return r, nil
+}
+
+// resolveColors replaces palette references in the colors of a style's JSON
+// representation: the values of "color" keys, such as "background_color",
+// and table row backgrounds.
+func resolveColors(v interface{}, colors map[string]string) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if strings.HasSuffix(k, "color") || k == "row_backgrounds" {
+				r, err := resolveColor(e, colors)
+				if err != nil {
+					return err
+				}
+				v[k] = r
+				continue
+			}
+			if err := resolveColors(e, colors); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if err := resolveColors(e, colors); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func resolveColor(v interface{}, colors map[string]string) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		if !strings.HasPrefix(v, paletteRefPrefix) {
+			return v, nil
+		}
+		c, ok := colors[strings.TrimPrefix(v, paletteRefPrefix)]
+		if !ok {
+			return nil, fmt.Errorf("glamour: %s: color not found in palette", v)
+		}
+		return c, nil
+	case []interface{}:
+		for i, e := range v {
+			r, err := resolveColor(e, colors)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = r
+		}
+	}
+	return v, nil
+}
@@ -29,7 +29,7 @@ func (e *ParagraphElement) Render(w io.Writer, ctx RenderContext) error {
 	bs.Push(be)
 
 	renderText(w, ctx.options.ColorProfile, bs.Parent().Style.StylePrimitive, rules.BlockPrefix)
-	renderText(bs.Current().Block, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.Prefix)
+	renderText(bs.Current().Block, ctx.options.ColorProfile, prefixStyle(bs.Current().Style.StylePrimitive), rules.Prefix)
 	return nil
 }
 
@@ -40,18 +40,24 @@ func (e *ParagraphElement) Finish(w io.Writer, ctx RenderContext) error {
 
 	mw := NewMarginWriter(ctx, w, rules)
 	if len(strings.TrimSpace(bs.Current().Block.String())) > 0 {
-		flow := wordwrap.NewWriter(int(bs.Width(ctx))) //nolint: gosec
-		flow.KeepNewlines = ctx.options.PreserveNewLines
-		_, _ = flow.Write(bs.Current().Block.Bytes())
-		if err := flow.Close(); err != nil {
-			return fmt.Errorf("glamour: error closing flow: %w", err)
-		}
+		// line breaks from <br> tags separate independently wrapped lines
+		for i, line := range bytes.Split(bs.Current().Block.Bytes(), []byte(lineBreak)) {
+			if i > 0 {
+				line = bytes.TrimLeft(line, " \n")
+			}
+			flow := wordwrap.NewWriter(int(bs.Width(ctx))) //nolint: gosec
+			flow.KeepNewlines = ctx.options.PreserveNewLines
+			_, _ = flow.Write(line)
+			if err := flow.Close(); err != nil {
+				return fmt.Errorf("glamour: error closing flow: %w", err)
+			}
 
-		_, err := mw.Write(flow.Bytes())
-		if err != nil {
-			return err
+			_, err := mw.Write(flow.Bytes())
+			if err != nil {
+				return err
+			}
+			_, _ = io.WriteString(mw, "\n")
 		}
-		_, _ = io.WriteString(mw, "\n")
 	}
 
 	renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.Suffix)
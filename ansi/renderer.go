@@ -6,6 +6,13 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/charmbracelet/glamour/internal/alert"
+	"github.com/charmbracelet/glamour/internal/details"
+	"github.com/charmbracelet/glamour/internal/frontmatter"
+	"github.com/charmbracelet/glamour/internal/htmlinline"
+	"github.com/charmbracelet/glamour/internal/spans"
+	"github.com/charmbracelet/glamour/internal/texmath"
+	"github.com/charmbracelet/glamour/internal/toc"
 	"github.com/muesli/termenv"
 	east "github.com/yuin/goldmark-emoji/ast"
 	"github.com/yuin/goldmark/ast"
@@ -24,6 +31,36 @@ type Options struct {
 	ColorProfile     termenv.Profile
 	Styles           StyleConfig
 	ChromaFormatter  string
+	TOC              TOCOptions
+	HeadingNumbering *HeadingNumbering
+	// FrontMatterHeader renders a document's front matter as a metadata
+	// header instead of hiding it.
+	FrontMatterHeader bool
+	// CodeOverflow defines how code lines exceeding the available width
+	// are rendered.
+	CodeOverflow CodeOverflow
+	// TabWidth expands tabs in code blocks to the given width, or to 8
+	// columns when zero.
+	TabWidth int
+	// LanguageDetection resolves language aliases and guesses the language
+	// of code blocks without one. Disabled when nil.
+	LanguageDetection *LanguageDetection
+	// StructuredData reformats JSON code blocks or draws JSON and YAML
+	// code blocks as trees. Disabled when nil.
+	StructuredData *StructuredData
+	// TableCards renders tables wider than the available width by more
+	// than TableCardThreshold cells as stacked cards, one per row.
+	TableCards         bool
+	TableCardThreshold int
+	// TablePaging splits tables too wide for the available width into
+	// pages of columns, each repeating the first column.
+	TablePaging bool
+	// TableNumbers right-aligns, styles and formats the numbers of
+	// numeric table columns. Disabled when nil.
+	TableNumbers *TableNumbers
+	// CollapseDetails collapses <details> sections without an open
+	// attribute, unless a fold map passed to StartFolds expands them.
+	CollapseDetails  bool
 	SkipImageHandler bool // When true, don't register image handler (for custom image renderers)
 }
 
@@ -54,6 +91,8 @@ func (r *ANSIRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(ast.KindDocument, r.renderNode)
 	reg.Register(ast.KindHeading, r.renderNode)
 	reg.Register(ast.KindBlockquote, r.renderNode)
+	reg.Register(alert.KindAlert, r.renderNode)
+	reg.Register(details.KindDetails, r.renderNode)
 	reg.Register(ast.KindCodeBlock, r.renderNode)
 	reg.Register(ast.KindFencedCodeBlock, r.renderNode)
 	reg.Register(ast.KindHTMLBlock, r.renderNode)
@@ -75,6 +114,8 @@ func (r *ANSIRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(ast.KindRawHTML, r.renderNode)
 	reg.Register(ast.KindText, r.renderNode)
 	reg.Register(ast.KindString, r.renderNode)
+	reg.Register(htmlinline.KindTag, r.renderNode)
+	reg.Register(spans.KindSpan, r.renderNode)
 
 	// tables
 	reg.Register(astext.KindTable, r.renderNode)
@@ -101,6 +142,16 @@ func (r *ANSIRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 
 	// emoji
 	reg.Register(east.KindEmoji, r.renderNode)
+
+	// table of contents
+	reg.Register(toc.KindTableOfContents, r.renderNode)
+
+	// front matter
+	reg.Register(frontmatter.KindFrontMatter, r.renderNode)
+
+	// math
+	reg.Register(texmath.KindInlineMath, r.renderNode)
+	reg.Register(texmath.KindDisplayMath, r.renderNode)
 }
 
 func (r *ANSIRenderer) renderNode(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
@@ -112,6 +163,16 @@ func (r *ANSIRenderer) renderNode(w util.BufWriter, source []byte, node ast.Node
 		return ast.WalkContinue, nil
 	}
 
+	if entering && node.Kind() == ast.KindDocument {
+		if r.context.err != nil {
+			return ast.WalkStop, r.context.err
+		}
+		r.context.meta = node.(*ast.Document).Meta()
+		if r.context.options.HeadingNumbering != nil {
+			r.context.headings.Reset(r.context.options.HeadingNumbering.StartLevel)
+		}
+	}
+
 	e := r.NewElement(node, source)
 	if entering { //nolint: nestif
 		// everything below the Document element gets rendered into a block buffer
@@ -126,6 +187,9 @@ func (r *ANSIRenderer) renderNode(w util.BufWriter, source []byte, node ast.Node
 				return ast.WalkStop, fmt.Errorf("glamour: error rendering: %w", err)
 			}
 		}
+		if e.SkipChildren {
+			return ast.WalkSkipChildren, nil
+		}
 	} else {
 		// everything below the Document element gets rendered into a block buffer
 		if bs.Len() > 0 {
@@ -155,7 +219,7 @@ func isChild(node ast.Node) bool {
 	for n := node.Parent(); n != nil; n = n.Parent() {
 		// These types are already rendered by their parent
 		switch n.Kind() {
-		case ast.KindCodeSpan, ast.KindAutoLink, ast.KindLink, ast.KindImage, ast.KindEmphasis, astext.KindStrikethrough, astext.KindTableCell:
+		case ast.KindCodeSpan, ast.KindAutoLink, ast.KindLink, ast.KindImage, ast.KindEmphasis, astext.KindStrikethrough, astext.KindTableCell, htmlinline.KindTag, spans.KindSpan:
 			return true
 		}
 	}
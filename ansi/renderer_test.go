@@ -147,3 +147,37 @@ func TestRendererIssues(t *testing.T) {
 		})
 	}
 }
+
+func TestRendererPalette(t *testing.T) {
+	tests := []struct {
+		name  string
+		style string
+		err   bool
+	}{
+		{name: "resolved", style: `{"palette": {"brand": "#00FF00"}, "document": {"color": "$brand"}}`},
+		{name: "missing", style: `{"document": {"color": "$missing"}}`, err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := Options{ColorProfile: termenv.TrueColor}
+			if err := json.Unmarshal([]byte(tc.style), &options.Styles); err != nil {
+				t.Fatal(err)
+			}
+
+			md := goldmark.New()
+			md.SetRenderer(
+				renderer.NewRenderer(
+					renderer.WithNodeRenderers(util.Prioritized(NewRenderer(options), 1000))))
+
+			var buf bytes.Buffer
+			err := md.Convert([]byte("Hello"), &buf)
+			if (err != nil) != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if !tc.err && !strings.Contains(buf.String(), "38;2;0;255;0") {
+				t.Errorf("expected the palette color in %q", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// SGR parameters termenv has no constants for.
+const (
+	concealSeq         = "8"
+	underlineColorSeq  = "58"
+	doubleUnderlineSeq = "4:2"
+	curlyUnderlineSeq  = "4:3"
+	dottedUnderlineSeq = "4:4"
+	dashedUnderlineSeq = "4:5"
+)
+
+// sgrStyle collects the SGR (Select Graphic Rendition) parameters of a
+// StylePrimitive. It works like termenv.Style, but also knows the attributes
+// termenv lacks: conceal, underline styles and underline colors.
+type sgrStyle struct {
+	params []string
+}
+
+// newSGRStyle returns the SGR parameters for the given rules. The parameters
+// are ordered like termenv orders them, so output stays byte-compatible.
+func newSGRStyle(p termenv.Profile, rules StylePrimitive) sgrStyle {
+	var s sgrStyle
+
+	if rules.Color != nil {
+		s = s.color(p.Color(*rules.Color), false)
+	}
+	if rules.BackgroundColor != nil {
+		s = s.color(p.Color(*rules.BackgroundColor), true)
+	}
+	if rules.Underline != nil && *rules.Underline {
+		s = s.underline(p, rules.UnderlineStyle, rules.UnderlineColor)
+	}
+	if rules.Bold != nil && *rules.Bold {
+		s = s.add(termenv.BoldSeq)
+	}
+	if rules.Italic != nil && *rules.Italic {
+		s = s.add(termenv.ItalicSeq)
+	}
+	if rules.CrossedOut != nil && *rules.CrossedOut {
+		s = s.add(termenv.CrossOutSeq)
+	}
+	if rules.Overlined != nil && *rules.Overlined {
+		s = s.add(termenv.OverlineSeq)
+	}
+	if rules.Inverse != nil && *rules.Inverse {
+		s = s.add(termenv.ReverseSeq)
+	}
+	if rules.Blink != nil && *rules.Blink {
+		s = s.add(termenv.BlinkSeq)
+	}
+	if rules.Faint != nil && *rules.Faint {
+		s = s.add(termenv.FaintSeq)
+	}
+	// hiding text only makes sense when it can be revealed again
+	if rules.Conceal != nil && *rules.Conceal && p != termenv.Ascii {
+		s = s.add(concealSeq)
+	}
+
+	return s
+}
+
+func (s sgrStyle) add(params ...string) sgrStyle {
+	for _, param := range params {
+		if param != "" {
+			s.params = append(s.params, param)
+		}
+	}
+	return s
+}
+
+func (s sgrStyle) color(c termenv.Color, bg bool) sgrStyle {
+	if c == nil {
+		return s
+	}
+	return s.add(c.Sequence(bg))
+}
+
+// underline adds an underline in the given style, single (the default),
+// double, curly, dotted or dashed, and color. Terminals without true color
+// support rarely know styled or colored underlines, so they get a plain one.
+func (s sgrStyle) underline(p termenv.Profile, style, color *string) sgrStyle {
+	if p != termenv.TrueColor {
+		return s.add(termenv.UnderlineSeq)
+	}
+
+	seq := termenv.UnderlineSeq
+	if style != nil {
+		switch *style {
+		case "double":
+			seq = doubleUnderlineSeq
+		case "curly":
+			seq = curlyUnderlineSeq
+		case "dotted":
+			seq = dottedUnderlineSeq
+		case "dashed":
+			seq = dashedUnderlineSeq
+		}
+	}
+	s = s.add(seq)
+
+	if color != nil {
+		s = s.underlineColor(p.Color(*color))
+	}
+	return s
+}
+
+// underlineColor adds an underline color (SGR 58). Underline colors use the
+// 256-color or true color forms only, so basic ANSI colors map to their
+// index in the 256-color palette.
+func (s sgrStyle) underlineColor(c termenv.Color) sgrStyle {
+	switch c := c.(type) {
+	case termenv.ANSIColor:
+		return s.add(fmt.Sprintf("%s;5;%d", underlineColorSeq, int(c)))
+	case termenv.ANSI256Color:
+		return s.add(fmt.Sprintf("%s;5;%d", underlineColorSeq, int(c)))
+	case termenv.RGBColor:
+		if c.Sequence(false) == "" {
+			return s // not a valid hex color
+		}
+		r, g, b := termenv.ConvertToRGB(c).RGB255()
+		return s.add(fmt.Sprintf("%s;2;%d;%d;%d", underlineColorSeq, r, g, b))
+	}
+	return s
+}
+
+// sequence returns the opening escape sequence, or "" if there is nothing to
+// set.
+func (s sgrStyle) sequence() string {
+	if len(s.params) == 0 {
+		return ""
+	}
+	return termenv.CSI + strings.Join(s.params, ";") + "m"
+}
+
+// wrapper returns the escape sequences to write before and after styled
+// text.
+func (s sgrStyle) wrapper() (prefix, suffix string) {
+	prefix = s.sequence()
+	if prefix == "" {
+		return "", ""
+	}
+	return prefix, termenv.CSI + termenv.ResetSeq + "m"
+}
+
+// styled wraps str in the style's escape sequences.
+func (s sgrStyle) styled(str string) string {
+	prefix, suffix := s.wrapper()
+	return prefix + str + suffix
+}
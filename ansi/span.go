@@ -0,0 +1,87 @@
+package ansi
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/glamour/internal/spans"
+	"github.com/charmbracelet/glamour/internal/texmath"
+)
+
+// A SpanElement is used to render inline spans like ==marked== text and
+// <kbd> elements. The prefix and suffix of its style surround the span once,
+// not every child.
+type SpanElement struct {
+	Style    StylePrimitive
+	Children []ElementRenderer
+
+	// Script is "sub" or "sup" for sub- and superscript spans. Their Text is
+	// rendered in Unicode sub- or superscript characters if every character
+	// has one, and their children in Style otherwise.
+	Script string
+	Text   string
+}
+
+// spanStyle returns the style of a span type.
+func spanStyle(ctx RenderContext, spanType string) StylePrimitive {
+	styles := ctx.options.Styles
+	switch spanType {
+	case spans.Mark:
+		return styles.Mark
+	case spans.Sup:
+		return styles.Sup
+	case spans.Sub:
+		return styles.Sub
+	case spans.Ins:
+		return styles.Ins
+	case spans.Spoiler:
+		return styles.Spoiler
+	}
+	return StylePrimitive{}
+}
+
+// scriptChars converts s to Unicode sub- or superscript characters. It
+// reports false if some character has no such form.
+func scriptChars(script, s string) (string, bool) {
+	switch script {
+	case "sub":
+		return texmath.Subscript(s)
+	case "sup":
+		return texmath.Superscript(s)
+	}
+	return "", false
+}
+
+// Render renders a SpanElement.
+func (e *SpanElement) Render(w io.Writer, ctx RenderContext) error {
+	return e.StyleOverrideRender(w, ctx, StylePrimitive{})
+}
+
+// StyleOverrideRender renders a SpanElement with a given style.
+func (e *SpanElement) StyleOverrideRender(w io.Writer, ctx RenderContext, style StylePrimitive) error {
+	if s, ok := scriptChars(e.Script, e.Text); ok {
+		el := &BaseElement{
+			Token: s,
+			Style: ctx.options.Styles.Text,
+		}
+		return el.StyleOverrideRender(w, ctx, style)
+	}
+
+	rules := cascadeStylePrimitives(style, e.Style)
+	p := ctx.options.ColorProfile
+	st := ctx.blockStack.With(rules)
+	renderText(w, p, st, rules.Prefix)
+	inner := rules
+	inner.Prefix, inner.Suffix = "", ""
+	for _, child := range e.Children {
+		if r, ok := child.(StyleOverriderElementRenderer); ok {
+			if err := r.StyleOverrideRender(w, ctx, inner); err != nil {
+				return fmt.Errorf("glamour: error rendering with style: %w", err)
+			}
+		} else if err := child.Render(w, ctx); err != nil {
+			return fmt.Errorf("glamour: error rendering: %w", err)
+		}
+	}
+	renderText(w, p, st, rules.Suffix)
+	return nil
+}
@@ -0,0 +1,100 @@
+package ansi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/glamour/internal/datatree"
+	"github.com/charmbracelet/glamour/internal/frontmatter"
+)
+
+// StructuredData configures how JSON and YAML code blocks are rendered.
+type StructuredData struct {
+	// Pretty reformats JSON blocks before highlighting them, indenting them
+	// by Indent spaces (2 if zero) and sorting object keys.
+	Pretty bool
+	Indent int
+
+	// Tree draws JSON and YAML blocks as a tree of their values instead of
+	// as code.
+	Tree bool
+
+	// MaxDepth collapses objects and arrays nested deeper than MaxDepth
+	// levels in the tree view. Zero means no limit.
+	MaxDepth int
+
+	// MaxItems shows at most MaxItems entries of each object or array in
+	// the tree view. Zero means no limit.
+	MaxItems int
+}
+
+var errTrailingData = errors.New("glamour: trailing data after JSON value")
+
+// decodeJSON decodes a single JSON value, keeping numbers as written.
+func decodeJSON(code string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(code))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if dec.More() {
+		return nil, errTrailingData
+	}
+	return v, nil
+}
+
+// pretty returns JSON code reformatted, or the code as is if it isn't valid
+// JSON.
+func (s *StructuredData) pretty(language, code string) string {
+	if !s.Pretty || !strings.EqualFold(language, "json") {
+		return code
+	}
+	v, err := decodeJSON(code)
+	if err != nil {
+		return code
+	}
+
+	indent := s.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", strings.Repeat(" ", indent))
+	if err := enc.Encode(v); err != nil {
+		return code
+	}
+	return b.String()
+}
+
+// tree returns the lines of the tree view of JSON or YAML code. It reports
+// false if the tree view is disabled or the code can't be parsed.
+func (s *StructuredData) tree(language, code string, width int) ([]string, bool) {
+	if !s.Tree {
+		return nil, false
+	}
+
+	var v interface{}
+	var err error
+	switch strings.ToLower(language) {
+	case "json":
+		v, err = decodeJSON(code)
+	case "yaml", "yml":
+		v, err = frontmatter.Parse(frontmatter.YAML, []byte(code))
+	default:
+		return nil, false
+	}
+	if err != nil {
+		return nil, false
+	}
+
+	return datatree.Render(v, datatree.Options{
+		MaxDepth: s.MaxDepth,
+		MaxItems: s.MaxItems,
+		Width:    width,
+	}), true
+}
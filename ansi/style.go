@@ -45,6 +45,8 @@ type StylePrimitive struct {
 	Color           *string `json:"color,omitempty"`
 	BackgroundColor *string `json:"background_color,omitempty"`
 	Underline       *bool   `json:"underline,omitempty"`
+	UnderlineStyle  *string `json:"underline_style,omitempty"`
+	UnderlineColor  *string `json:"underline_color,omitempty"`
 	Bold            *bool   `json:"bold,omitempty"`
 	Upper           *bool   `json:"upper,omitempty"`
 	Lower           *bool   `json:"lower,omitempty"`
@@ -86,8 +88,33 @@ type StyleBlock struct {
 // StyleCodeBlock holds the style settings for a code block.
 type StyleCodeBlock struct {
 	StyleBlock
-	Theme  string  `json:"theme,omitempty"`
-	Chroma *Chroma `json:"chroma,omitempty"`
+	Theme      string         `json:"theme,omitempty"`
+	Chroma     *Chroma        `json:"chroma,omitempty"`
+	Title      StylePrimitive `json:"title,omitempty"`
+	LineNumber StylePrimitive `json:"line_number,omitempty"`
+	Highlight  StylePrimitive `json:"highlight,omitempty"`
+	WrapMarker string         `json:"wrap_marker,omitempty"`
+	Ellipsis   string         `json:"ellipsis,omitempty"`
+	Diff       StyleDiff      `json:"diff,omitempty"`
+
+	// Border draws the code block inside a border of the given style:
+	// normal, rounded, thick, double, block, hidden or ascii.
+	Border        string         `json:"border,omitempty"`
+	BorderColor   *string        `json:"border_color,omitempty"`
+	Label         StylePrimitive `json:"label,omitempty"`
+	LabelPosition string         `json:"label_position,omitempty"`
+}
+
+// StyleDiff holds the style settings for unified diffs in code blocks.
+type StyleDiff struct {
+	Added   StylePrimitive `json:"added,omitempty"`
+	Removed StylePrimitive `json:"removed,omitempty"`
+	Context StylePrimitive `json:"context,omitempty"`
+	Hunk    StylePrimitive `json:"hunk,omitempty"`
+	Header  StylePrimitive `json:"header,omitempty"`
+	// SyntaxHighlighting highlights changed code in the language of the
+	// diffed file.
+	SyntaxHighlighting bool `json:"syntax_highlighting,omitempty"`
 }
 
 // StyleList holds the style settings for a list.
@@ -96,16 +123,107 @@ type StyleList struct {
 	LevelIndent uint `json:"level_indent,omitempty"`
 }
 
+// StyleAlert holds the style settings for an alert, such as a [!NOTE] or
+// [!WARNING] block. The block's indent token is used as the alert's border.
+type StyleAlert struct {
+	StyleBlock
+	Icon   string         `json:"icon,omitempty"`
+	Title  string         `json:"title,omitempty"`
+	Accent StylePrimitive `json:"accent,omitempty"`
+}
+
+// StyleDetails holds the style settings for a <details> section. The
+// Collapsed and Expanded markers precede its summary, which is styled by
+// StyleConfig.Summary, and the block style applies to its body.
+type StyleDetails struct {
+	StyleBlock
+	Collapsed string `json:"collapsed,omitempty"`
+	Expanded  string `json:"expanded,omitempty"`
+}
+
+// StyleTOC holds the style settings for a table of contents.
+type StyleTOC struct {
+	StyleBlock
+	Entry       StylePrimitive `json:"entry,omitempty"`
+	ID          StylePrimitive `json:"id,omitempty"`
+	LevelIndent uint           `json:"level_indent,omitempty"`
+}
+
+// StyleFrontMatter holds the style settings for a document's metadata
+// header, rendered from its front matter.
+type StyleFrontMatter struct {
+	StyleBlock
+	Title     StylePrimitive `json:"title,omitempty"`
+	Author    StylePrimitive `json:"author,omitempty"`
+	Date      StylePrimitive `json:"date,omitempty"`
+	Tag       StylePrimitive `json:"tag,omitempty"`
+	Separator string         `json:"separator,omitempty"`
+}
+
+// StyleMath holds the style settings for math. The block style applies to
+// display math, Inline to math within text.
+type StyleMath struct {
+	StyleBlock
+	Inline StylePrimitive `json:"inline,omitempty"`
+}
+
 // StyleTable holds the style settings for a table.
 type StyleTable struct {
 	StyleBlock
 	CenterSeparator *string `json:"center_separator,omitempty"`
 	ColumnSeparator *string `json:"column_separator,omitempty"`
 	RowSeparator    *string `json:"row_separator,omitempty"`
+
+	// Border names the border set used instead of the separators (normal,
+	// rounded, thick, double, block, hidden or ascii). BorderChars
+	// overrides single characters of it.
+	Border      string      `json:"border,omitempty"`
+	BorderChars StyleBorder `json:"border_chars,omitempty"`
+	BorderColor *string     `json:"border_color,omitempty"`
+
+	// OuterBorder draws the border around the table. HeaderUnderline draws
+	// the line below the header and defaults to true.
+	OuterBorder     *bool `json:"outer_border,omitempty"`
+	HeaderUnderline *bool `json:"header_underline,omitempty"`
+
+	// Header styles the header cells, Columns the body cells of each
+	// column. RowBackgrounds cycles through background colors for the
+	// body rows, an empty color leaving a row's background as is.
+	Header         StylePrimitive   `json:"header,omitempty"`
+	Columns        []StylePrimitive `json:"columns,omitempty"`
+	RowBackgrounds []string         `json:"row_backgrounds,omitempty"`
+
+	// Number styles the cells of numeric columns, NegativeNumber their
+	// negative numbers. They apply when table numbers are formatted.
+	Number         StylePrimitive `json:"number,omitempty"`
+	NegativeNumber StylePrimitive `json:"negative_number,omitempty"`
+
+	// Caption styles the captions of tables split into pages of columns.
+	Caption StylePrimitive `json:"caption,omitempty"`
+}
+
+// StyleBorder holds the characters of a table border.
+type StyleBorder struct {
+	Top          *string `json:"top,omitempty"`
+	Bottom       *string `json:"bottom,omitempty"`
+	Left         *string `json:"left,omitempty"`
+	Right        *string `json:"right,omitempty"`
+	TopLeft      *string `json:"top_left,omitempty"`
+	TopRight     *string `json:"top_right,omitempty"`
+	BottomLeft   *string `json:"bottom_left,omitempty"`
+	BottomRight  *string `json:"bottom_right,omitempty"`
+	MiddleLeft   *string `json:"middle_left,omitempty"`
+	MiddleRight  *string `json:"middle_right,omitempty"`
+	Middle       *string `json:"middle,omitempty"`
+	MiddleTop    *string `json:"middle_top,omitempty"`
+	MiddleBottom *string `json:"middle_bottom,omitempty"`
 }
 
 // StyleConfig is used to configure the styling behavior of an ANSIRenderer.
 type StyleConfig struct {
+	// Palette names colors, which other colors can refer to as "$name".
+	Palette map[string]string `json:"palette,omitempty"`
+
 	Document   StyleBlock `json:"document,omitempty"`
 	BlockQuote StyleBlock `json:"block_quote,omitempty"`
 	Paragraph  StyleBlock `json:"paragraph,omitempty"`
@@ -140,12 +258,35 @@ type StyleConfig struct {
 
 	Table StyleTable `json:"table,omitempty"`
 
+	Alerts map[string]StyleAlert `json:"alerts,omitempty"`
+
+	TOC StyleTOC `json:"toc,omitempty"`
+
+	FrontMatter StyleFrontMatter `json:"front_matter,omitempty"`
+
+	Math StyleMath `json:"math,omitempty"`
+
 	DefinitionList        StyleBlock     `json:"definition_list,omitempty"`
 	DefinitionTerm        StylePrimitive `json:"definition_term,omitempty"`
 	DefinitionDescription StylePrimitive `json:"definition_description,omitempty"`
 
 	HTMLBlock StyleBlock `json:"html_block,omitempty"`
 	HTMLSpan  StyleBlock `json:"html_span,omitempty"`
+
+	Details StyleDetails `json:"details,omitempty"`
+
+	// Styles of HTML elements and extended inline syntax without a
+	// CommonMark equivalent. Sub and Sup only apply to text without Unicode
+	// sub- or superscript characters. Annotation styles <u>, which marks
+	// text such as misspelled words.
+	Kbd        StylePrimitive `json:"kbd,omitempty"`
+	Mark       StylePrimitive `json:"mark,omitempty"`
+	Ins        StylePrimitive `json:"ins,omitempty"`
+	Annotation StylePrimitive `json:"annotation,omitempty"`
+	Sub        StylePrimitive `json:"sub,omitempty"`
+	Sup        StylePrimitive `json:"sup,omitempty"`
+	Spoiler    StylePrimitive `json:"spoiler,omitempty"`
+	Summary    StylePrimitive `json:"summary,omitempty"`
 }
 
 func cascadeStyles(s ...StyleBlock) StyleBlock {
@@ -170,6 +311,8 @@ func cascadeStylePrimitive(parent, child StylePrimitive, toBlock bool) StylePrim
 	s.Color = parent.Color
 	s.BackgroundColor = parent.BackgroundColor
 	s.Underline = parent.Underline
+	s.UnderlineStyle = parent.UnderlineStyle
+	s.UnderlineColor = parent.UnderlineColor
 	s.Bold = parent.Bold
 	s.Upper = parent.Upper
 	s.Title = parent.Title
@@ -194,6 +337,7 @@ func cascadeStylePrimitive(parent, child StylePrimitive, toBlock bool) StylePrim
 		s.BlockPrefix = parent.BlockPrefix
 		s.BlockSuffix = parent.BlockSuffix
 		s.Prefix = parent.Prefix
+		s.PrefixColor = parent.PrefixColor
 		s.Suffix = parent.Suffix
 	}
 
@@ -206,6 +350,12 @@ func cascadeStylePrimitive(parent, child StylePrimitive, toBlock bool) StylePrim
 	if child.Underline != nil {
 		s.Underline = child.Underline
 	}
+	if child.UnderlineStyle != nil {
+		s.UnderlineStyle = child.UnderlineStyle
+	}
+	if child.UnderlineColor != nil {
+		s.UnderlineColor = child.UnderlineColor
+	}
 	if child.Bold != nil {
 		s.Bold = child.Bold
 	}
@@ -248,6 +398,9 @@ func cascadeStylePrimitive(parent, child StylePrimitive, toBlock bool) StylePrim
 	if child.Prefix != "" {
 		s.Prefix = child.Prefix
 	}
+	if child.PrefixColor != nil {
+		s.PrefixColor = child.PrefixColor
+	}
 	if child.Suffix != "" {
 		s.Suffix = child.Suffix
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
@@ -19,6 +20,14 @@ type TableElement struct {
 	row      []string
 	source   []byte
 
+	// headers and rows hold the rendered cells of the whole table, used to
+	// render it as cards or pages.
+	headers []string
+	rows    [][]string
+
+	// numeric reports which columns hold numbers only.
+	numeric []bool
+
 	tableImages []tableLink
 	tableLinks  []tableLink
 }
@@ -57,51 +66,126 @@ func (e *TableElement) Render(w io.Writer, ctx RenderContext) error {
 
 	renderText(iw, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockPrefix)
 	renderText(iw, ctx.options.ColorProfile, style, rules.Prefix)
+	ctx.table.lipgloss = newTable(ctx)
+
+	if err := e.collectLinksAndImages(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (e *TableElement) setStyles(ctx RenderContext) {
+	ctx.table.lipgloss = ctx.table.lipgloss.StyleFunc(e.styleFunc(ctx))
+}
+
+func (e *TableElement) styleFunc(ctx RenderContext) table.StyleFunc {
+	return func(row, col int) lipgloss.Style {
+		align := e.table.Alignments[col]
+		if align == astext.AlignNone && col < len(ctx.table.numeric) && ctx.table.numeric[col] {
+			align = astext.AlignRight
+		}
+		return tableCellStyle(ctx, row, align)
+	}
+}
+
+func (e *TableElement) setBorders(ctx RenderContext) {
+	setTableBorders(ctx, ctx.table.lipgloss)
+}
+
+// newTable returns a lipgloss table as wide as the current block.
+func newTable(ctx RenderContext) *table.Table {
 	width := int(ctx.blockStack.Width(ctx)) //nolint: gosec
 
 	wrap := true
 	if ctx.options.TableWrap != nil {
 		wrap = *ctx.options.TableWrap
 	}
-	ctx.table.lipgloss = table.New().Width(width).Wrap(wrap)
+	return table.New().Width(width).Wrap(wrap)
+}
 
-	if err := e.collectLinksAndImages(ctx); err != nil {
-		return err
+// tableCellStyle returns the style of a table cell with the given alignment.
+// Body rows are painted with the table's row backgrounds.
+func tableCellStyle(ctx RenderContext, row int, align astext.Alignment) lipgloss.Style {
+	st := ctx.lipgloss.NewStyle().Inline(false)
+	// Default Styles
+	st = st.Margin(0, 1)
+
+	// Override with custom styles
+	if m := ctx.options.Styles.Table.Margin; m != nil {
+		st = st.Padding(0, int(*m)) //nolint: gosec
+	}
+	switch align {
+	case astext.AlignLeft:
+		st = st.Align(lipgloss.Left).PaddingRight(0)
+	case astext.AlignCenter:
+		st = st.Align(lipgloss.Center)
+	case astext.AlignRight:
+		st = st.Align(lipgloss.Right).PaddingLeft(0)
+	case astext.AlignNone:
+		// do nothing
+	}
+	if bg := tableRowBackground(ctx, row); bg != nil {
+		c := lipgloss.Color(*bg)
+		st = st.Background(c).MarginBackground(c)
 	}
 
-	return nil
+	return st
 }
 
-func (e *TableElement) setStyles(ctx RenderContext) {
-	ctx.table.lipgloss = ctx.table.lipgloss.StyleFunc(func(_, col int) lipgloss.Style {
-		st := lipgloss.NewStyle().Inline(false)
-		// Default Styles
-		st = st.Margin(0, 1)
-
-		// Override with custom styles
-		if m := ctx.options.Styles.Table.Margin; m != nil {
-			st = st.Padding(0, int(*m)) //nolint: gosec
-		}
-		switch e.table.Alignments[col] {
-		case astext.AlignLeft:
-			st = st.Align(lipgloss.Left).PaddingRight(0)
-		case astext.AlignCenter:
-			st = st.Align(lipgloss.Center)
-		case astext.AlignRight:
-			st = st.Align(lipgloss.Right).PaddingLeft(0)
-		case astext.AlignNone:
-			// do nothing
-		}
+// tableCellPrimitive returns the style of a header cell, or of a body cell
+// in the given column.
+func tableCellPrimitive(ctx RenderContext, head bool, col int) StylePrimitive {
+	rules := ctx.options.Styles.Table
+	if head {
+		return cascadeStylePrimitives(rules.StylePrimitive, rules.Header)
+	}
+	if col < len(rules.Columns) {
+		return cascadeStylePrimitives(rules.StylePrimitive, rules.Columns[col])
+	}
+	return rules.StylePrimitive
+}
 
-		return st
-	})
+// tableRowBackground returns the background color of a body row, or nil.
+func tableRowBackground(ctx RenderContext, row int) *string {
+	bgs := ctx.options.Styles.Table.RowBackgrounds
+	if row < 0 || len(bgs) == 0 || bgs[row%len(bgs)] == "" {
+		return nil
+	}
+	return &bgs[row%len(bgs)]
 }
 
-func (e *TableElement) setBorders(ctx RenderContext) {
+// paintTableRow keeps a body row's background behind its cells' text by
+// restoring it after each reset.
+func paintTableRow(ctx RenderContext, row int, cells []string) []string {
+	bg := backgroundSequence(ctx.options.ColorProfile, tableRowBackground(ctx, row))
+	if bg == "" {
+		return cells
+	}
+	painted := make([]string, len(cells))
+	for i, c := range cells {
+		painted[i] = strings.ReplaceAll(c, "\x1b[0m", "\x1b[0m"+bg)
+	}
+	return painted
+}
+
+// paintTableRows paints each of the body rows with its background.
+func paintTableRows(ctx RenderContext, rows [][]string) [][]string {
+	painted := make([][]string, len(rows))
+	for i, r := range rows {
+		painted[i] = paintTableRow(ctx, i, r)
+	}
+	return painted
+}
+
+// setTableBorders applies the table style's border to t.
+func setTableBorders(ctx RenderContext, t *table.Table) {
 	rules := ctx.options.Styles.Table
 	border := lipgloss.NormalBorder()
 
-	if rules.RowSeparator != nil && rules.ColumnSeparator != nil {
+	if b, ok := borderStyles[rules.Border]; ok {
+		border = b
+	} else if rules.RowSeparator != nil && rules.ColumnSeparator != nil {
 		border = lipgloss.Border{
 			Top:    *rules.RowSeparator,
 			Bottom: *rules.RowSeparator,
@@ -110,11 +194,45 @@ func (e *TableElement) setBorders(ctx RenderContext) {
 			Middle: *rules.CenterSeparator,
 		}
 	}
-	ctx.table.lipgloss.Border(border)
-	ctx.table.lipgloss.BorderTop(false)
-	ctx.table.lipgloss.BorderLeft(false)
-	ctx.table.lipgloss.BorderRight(false)
-	ctx.table.lipgloss.BorderBottom(false)
+	border = rules.BorderChars.apply(border)
+
+	outer := rules.OuterBorder != nil && *rules.OuterBorder
+	t.Border(border)
+	t.BorderTop(outer)
+	t.BorderLeft(outer)
+	t.BorderRight(outer)
+	t.BorderBottom(outer)
+	t.BorderHeader(rules.HeaderUnderline == nil || *rules.HeaderUnderline)
+	if rules.BorderColor != nil {
+		t.BorderStyle(ctx.lipgloss.NewStyle().Foreground(lipgloss.Color(*rules.BorderColor)))
+	}
+}
+
+// apply returns border with the characters set in b replaced.
+func (b StyleBorder) apply(border lipgloss.Border) lipgloss.Border {
+	for _, c := range []struct {
+		from *string
+		to   *string
+	}{
+		{b.Top, &border.Top},
+		{b.Bottom, &border.Bottom},
+		{b.Left, &border.Left},
+		{b.Right, &border.Right},
+		{b.TopLeft, &border.TopLeft},
+		{b.TopRight, &border.TopRight},
+		{b.BottomLeft, &border.BottomLeft},
+		{b.BottomRight, &border.BottomRight},
+		{b.MiddleLeft, &border.MiddleLeft},
+		{b.MiddleRight, &border.MiddleRight},
+		{b.Middle, &border.Middle},
+		{b.MiddleTop, &border.MiddleTop},
+		{b.MiddleBottom, &border.MiddleBottom},
+	} {
+		if c.from != nil {
+			*c.to = *c.from
+		}
+	}
+	return border
 }
 
 // Finish finishes rendering a TableElement.
@@ -123,15 +241,36 @@ func (e *TableElement) Finish(_ io.Writer, ctx RenderContext) error {
 		ctx.table.lipgloss = nil
 		ctx.table.tableImages = nil
 		ctx.table.tableLinks = nil
+		ctx.table.headers = nil
+		ctx.table.rows = nil
+		ctx.table.numeric = nil
 	}()
 
 	rules := ctx.options.Styles.Table
 
+	if ctx.options.TableNumbers != nil {
+		ctx.table.numeric = numericTableColumns(ctx.table.rows)
+		rows, err := formatTableNumbers(ctx, ctx.table.rows, ctx.table.numeric)
+		if err != nil {
+			return err
+		}
+		ctx.table.rows = rows
+	}
+	// rows are painted last, so numbers are detected in their plain cells
+	ctx.table.lipgloss.Rows(paintTableRows(ctx, ctx.table.rows)...)
+
 	e.setStyles(ctx)
 	e.setBorders(ctx)
 
+	out := ctx.table.lipgloss.String()
+	if cards, ok := tableCards(ctx, ctx.table.headers, ctx.table.rows, e.styleFunc(ctx)); ok {
+		out = cards
+	} else if pages, ok := tablePages(ctx, ctx.table.headers, ctx.table.rows, e.styleFunc(ctx)); ok {
+		out = pages
+	}
+
 	ow := ctx.blockStack.Current().Block
-	if _, err := ow.WriteString(ctx.table.lipgloss.String()); err != nil {
+	if _, err := ow.WriteString(out); err != nil {
 		return fmt.Errorf("glamour: error writing to buffer: %w", err)
 	}
 
@@ -149,7 +288,7 @@ func (e *TableRowElement) Finish(_ io.Writer, ctx RenderContext) error {
 		return nil
 	}
 
-	ctx.table.lipgloss.Row(ctx.table.row...)
+	ctx.table.rows = append(ctx.table.rows, ctx.table.row)
 	ctx.table.row = []string{}
 	return nil
 }
@@ -161,6 +300,7 @@ func (e *TableHeadElement) Finish(_ io.Writer, ctx RenderContext) error {
 	}
 
 	ctx.table.lipgloss.Headers(ctx.table.header...)
+	ctx.table.headers = ctx.table.header
 	ctx.table.header = []string{}
 	return nil
 }
@@ -168,7 +308,11 @@ func (e *TableHeadElement) Finish(_ io.Writer, ctx RenderContext) error {
 // Render renders a TableCellElement.
 func (e *TableCellElement) Render(_ io.Writer, ctx RenderContext) error {
 	var b bytes.Buffer
-	style := ctx.options.Styles.Table.StylePrimitive
+	col := len(ctx.table.row)
+	if e.Head {
+		col = len(ctx.table.header)
+	}
+	style := tableCellPrimitive(ctx, e.Head, col)
 	for _, child := range e.Children {
 		if r, ok := child.(StyleOverriderElementRenderer); ok {
 			if err := r.StyleOverrideRender(&b, ctx, style); err != nil {
@@ -0,0 +1,105 @@
+package ansi
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+// minCardValueWidth is the minimum width of a card's values. Narrower values
+// are moved below their header.
+const minCardValueWidth = 10
+
+// tableCards renders the rows of a table as vertical cards of "header: value"
+// pairs, separated by rules. It reports false if cards are disabled or the
+// table's natural width doesn't exceed the available width by more than the
+// configured threshold.
+func tableCards(ctx RenderContext, headers []string, rows [][]string, style table.StyleFunc) (string, bool) {
+	if !ctx.options.TableCards {
+		return "", false
+	}
+
+	t := table.New().Headers(headers...).Rows(rows...).StyleFunc(style)
+	setTableBorders(ctx, t)
+	width := int(ctx.blockStack.Width(ctx)) //nolint: gosec
+	if lipgloss.Width(t.String()) <= width+ctx.options.TableCardThreshold {
+		return "", false
+	}
+
+	// cards are indented like the cells of a table
+	rules := ctx.options.Styles.Table
+	margin := 1
+	if rules.Margin != nil {
+		margin += int(*rules.Margin) //nolint: gosec
+	}
+	pad := strings.Repeat(" ", margin)
+	width -= margin * 2
+
+	var labelWidth int
+	for _, h := range headers {
+		labelWidth = max(labelWidth, xansi.StringWidth(h))
+	}
+	valueWidth := width - labelWidth - 2
+	stacked := valueWidth < minCardValueWidth
+	if stacked {
+		valueWidth = width - 2
+	}
+	if labelWidth == 0 {
+		// tables without headers list their values only
+		valueWidth = width
+	}
+
+	sep := "─"
+	if rules.RowSeparator != nil {
+		sep = *rules.RowSeparator
+	}
+	var rule strings.Builder
+	renderText(&rule, ctx.options.ColorProfile, ctx.blockStack.With(rules.StylePrimitive), strings.Repeat(sep, max(width, 1)))
+
+	var b strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(pad + rule.String() + "\n")
+		}
+		bg := backgroundSequence(ctx.options.ColorProfile, tableRowBackground(ctx, i))
+		for col, cell := range paintTableRow(ctx, i, row) {
+			var header string
+			if col < len(headers) {
+				header = headers[col]
+			}
+			value := strings.Split(xansi.Wrap(cell, max(valueWidth, 1), ""), "\n")
+			if bg != "" {
+				// each line of a value gets its row's background, ending
+				// with a reset so it doesn't leak into the next line
+				for j, l := range value {
+					value[j] = bg + l + "\x1b[0m"
+				}
+			}
+			if labelWidth == 0 {
+				for _, l := range value {
+					b.WriteString(pad + l + "\n")
+				}
+				continue
+			}
+
+			b.WriteString(pad + header)
+			if stacked {
+				b.WriteString("\n")
+				for _, l := range value {
+					b.WriteString(pad + "  " + l + "\n")
+				}
+				continue
+			}
+			b.WriteString(strings.Repeat(" ", labelWidth-xansi.StringWidth(header)) + ": ")
+			for j, l := range value {
+				if j > 0 {
+					b.WriteString(pad + strings.Repeat(" ", labelWidth+2))
+				}
+				b.WriteString(l + "\n")
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n"), true
+}
@@ -204,6 +204,10 @@ func nodeContent(node ast.Node, source []byte) ([]byte, error) {
 				if _, err := builder.Write(nn.Segment.Value(source)); err != nil {
 					return fmt.Errorf("glamour: error writing text node: %w", err)
 				}
+			case *ast.String:
+				if _, err := builder.Write(nn.Value); err != nil {
+					return fmt.Errorf("glamour: error writing string node: %w", err)
+				}
 			default:
 				if err := traverse(nn); err != nil {
 					return err
@@ -0,0 +1,98 @@
+package ansi
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour/internal/numfmt"
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+// TableNumbers configures how numeric table columns, whose body cells all
+// hold numbers, are rendered. Numeric columns without an explicit alignment
+// are right-aligned, and their cells are styled with the table style's
+// Number and NegativeNumber.
+type TableNumbers struct {
+	// AlignDecimal lines up the decimal points of a column's numbers.
+	AlignDecimal bool
+
+	// GroupSeparator groups the integer digits of numbers in thousands,
+	// e.g. with "," or " ". Numbers are left as is when empty.
+	GroupSeparator string
+}
+
+// numericTableColumns reports which columns of rendered rows hold numbers
+// only, ignoring empty cells.
+func numericTableColumns(rows [][]string) []bool {
+	var columns int
+	for _, r := range rows {
+		columns = max(columns, len(r))
+	}
+
+	numeric := make([]bool, columns)
+	for col := range numeric {
+		for _, r := range rows {
+			if col >= len(r) {
+				continue
+			}
+			s := strings.TrimSpace(xansi.Strip(r[col]))
+			if s == "" {
+				continue
+			}
+			numeric[col] = numfmt.IsNumber(s)
+			if !numeric[col] {
+				break
+			}
+		}
+	}
+	return numeric
+}
+
+// formatTableNumbers re-renders the cells of numeric columns with the table
+// style's number styles, grouping their digits and aligning their decimal
+// points as configured.
+func formatTableNumbers(ctx RenderContext, rows [][]string, numeric []bool) ([][]string, error) {
+	opts := ctx.options.TableNumbers
+	rules := ctx.options.Styles.Table
+
+	formatted := make([][]string, len(rows))
+	for i, r := range rows {
+		formatted[i] = append([]string{}, r...)
+	}
+
+	for col, ok := range numeric {
+		if !ok {
+			continue
+		}
+
+		numbers := make([]string, len(rows))
+		for i, r := range rows {
+			if col < len(r) {
+				numbers[i] = numfmt.Group(strings.TrimSpace(xansi.Strip(r[col])), opts.GroupSeparator)
+			}
+		}
+		if opts.AlignDecimal {
+			numbers = numfmt.Align(numbers)
+		}
+
+		for i, n := range numbers {
+			if n == "" {
+				continue
+			}
+			style := cascadeStylePrimitives(tableCellPrimitive(ctx, false, col), rules.Number)
+			if numfmt.IsNegative(n) {
+				style = cascadeStylePrimitives(style, rules.NegativeNumber)
+			}
+
+			var b strings.Builder
+			el := &BaseElement{
+				Token: n,
+				Style: style,
+			}
+			if err := el.Render(&b, ctx); err != nil {
+				return nil, err
+			}
+			formatted[i][col] = b.String()
+		}
+	}
+	return formatted, nil
+}
@@ -0,0 +1,134 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// tablePages splits a table too wide for the available width into groups of
+// columns that fit, each repeating the first (key) column and captioned with
+// the columns it shows. It reports false if paging is disabled, the table
+// fits, or it has too few columns to split.
+func tablePages(ctx RenderContext, headers []string, rows [][]string, style table.StyleFunc) (string, bool) {
+	if !ctx.options.TablePaging {
+		return "", false
+	}
+	rows = paintTableRows(ctx, rows)
+
+	columns := len(headers)
+	for _, r := range rows {
+		columns = max(columns, len(r))
+	}
+	width := int(ctx.blockStack.Width(ctx)) //nolint: gosec
+	if columns < 3 || naturalWidth(ctx, headers, rows, style, allColumns(columns)) <= width {
+		return "", false
+	}
+
+	// greedily fill each page with as many columns as fit next to the key
+	var pages [][]int
+	page := []int{0}
+	for col := 1; col < columns; col++ {
+		next := append(append([]int{}, page...), col)
+		if len(page) > 1 && naturalWidth(ctx, headers, rows, style, next) > width {
+			pages = append(pages, page)
+			next = []int{0, col}
+		}
+		page = next
+	}
+	pages = append(pages, page)
+
+	rules := ctx.options.Styles.Table
+	margin := 1
+	if rules.Margin != nil {
+		margin += int(*rules.Margin) //nolint: gosec
+	}
+	pad := strings.Repeat(" ", margin)
+
+	var b strings.Builder
+	for i, page := range pages {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+
+		el := &BaseElement{
+			Token: pageCaption(page, columns),
+			Style: cascadeStylePrimitives(rules.StylePrimitive, rules.Caption),
+		}
+		var cb strings.Builder
+		if err := el.Render(&cb, ctx); err != nil {
+			return "", false
+		}
+		b.WriteString(pad + cb.String() + "\n")
+
+		t := pageTable(ctx, headers, rows, style, page).Width(width)
+		b.WriteString(t.String())
+	}
+	return b.String(), true
+}
+
+// pageCaption describes the columns a page shows, including the key column,
+// joining consecutive columns into ranges, e.g. "columns 1, 4–5 of 6".
+func pageCaption(page []int, columns int) string {
+	var ranges []string
+	for i := 0; i < len(page); {
+		j := i
+		for j+1 < len(page) && page[j+1] == page[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, fmt.Sprintf("%d", page[i]+1))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d–%d", page[i]+1, page[j]+1))
+		}
+		i = j + 1
+	}
+	return fmt.Sprintf("columns %s of %d", strings.Join(ranges, ", "), columns)
+}
+
+// naturalWidth returns the width of the given columns of a table, rendered
+// without a width limit.
+func naturalWidth(ctx RenderContext, headers []string, rows [][]string, style table.StyleFunc, columns []int) int {
+	return lipgloss.Width(pageTable(ctx, headers, rows, style, columns).String())
+}
+
+// pageTable returns a table holding the given columns of a table.
+func pageTable(ctx RenderContext, headers []string, rows [][]string, style table.StyleFunc, columns []int) *table.Table {
+	pick := func(cells []string) []string {
+		picked := make([]string, 0, len(columns))
+		for _, c := range columns {
+			if c < len(cells) {
+				picked = append(picked, cells[c])
+			} else {
+				picked = append(picked, "")
+			}
+		}
+		return picked
+	}
+
+	wrap := true
+	if ctx.options.TableWrap != nil {
+		wrap = *ctx.options.TableWrap
+	}
+	t := table.New().Wrap(wrap).StyleFunc(func(row, col int) lipgloss.Style {
+		return style(row, columns[col])
+	})
+	if headers != nil {
+		t.Headers(pick(headers)...)
+	}
+	for _, r := range rows {
+		t.Row(pick(r)...)
+	}
+	setTableBorders(ctx, t)
+	return t
+}
+
+func allColumns(n int) []int {
+	columns := make([]int, n)
+	for i := range columns {
+		columns[i] = i
+	}
+	return columns
+}
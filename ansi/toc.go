@@ -0,0 +1,146 @@
+package ansi
+
+import (
+	"io"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// TOCOptions configures how a table of contents is rendered.
+type TOCOptions struct {
+	// MinLevel and MaxLevel limit which heading levels are listed. They
+	// default to 1 and 6.
+	MinLevel int
+	MaxLevel int
+
+	// Numbered prefixes every entry with its section number, e.g. "1.2".
+	// With heading numbering enabled, entries get the same section numbers as
+	// the headings themselves.
+	Numbered bool
+
+	// ShowIDs appends each heading's auto-generated ID to its entry.
+	ShowIDs bool
+}
+
+// A TOCElement is used to render a table of contents.
+type TOCElement struct {
+	Block *BlockElement
+
+	node   ast.Node
+	source []byte
+}
+
+// A TOCEntry is a single heading listed in a table of contents.
+type TOCEntry struct {
+	Level  int
+	Text   string
+	ID     string
+	Number string
+}
+
+// tocEntries collects the headings of the document containing node. Entries
+// are counted from numbering's start level when it's set, so their numbers
+// match the headings'.
+func tocEntries(node ast.Node, source []byte, opts TOCOptions, numbering *HeadingNumbering) []TOCEntry {
+	minLevel, maxLevel := opts.MinLevel, opts.MaxLevel
+	if minLevel < h1 {
+		minLevel = h1
+	}
+	if maxLevel < minLevel || maxLevel > h6 {
+		maxLevel = h6
+	}
+
+	doc := node.OwnerDocument()
+	if doc == nil {
+		return nil
+	}
+
+	var entries []TOCEntry
+	counter := headingCounter{StartLevel: minLevel}
+	if numbering != nil {
+		counter.StartLevel = numbering.StartLevel
+	}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Kind() != ast.KindHeading {
+			return ast.WalkContinue, nil
+		}
+		h := n.(*ast.Heading)
+		// count every heading, including unlisted ones, like the renderer
+		numbers := counter.Next(h.Level)
+		if h.Level < minLevel || h.Level > maxLevel {
+			return ast.WalkSkipChildren, nil
+		}
+
+		content, _ := nodeContent(h, source)
+		entry := TOCEntry{
+			Level: h.Level - minLevel,
+			Text:  strings.TrimSpace(string(content)),
+		}
+		if id, ok := h.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				entry.ID = string(b)
+			}
+		}
+		if opts.Numbered {
+			entry.Number = joinNumbers(numbers)
+		}
+		entries = append(entries, entry)
+		return ast.WalkSkipChildren, nil
+	})
+
+	return entries
+}
+
+// Render renders a TOCElement.
+func (e *TOCElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.TOC
+
+	if err := e.Block.Render(w, ctx); err != nil {
+		return err
+	}
+
+	b := bs.Current().Block
+	for i, entry := range tocEntries(e.node, e.source, ctx.options.TOC, ctx.options.HeadingNumbering) {
+		if i > 0 {
+			_, _ = io.WriteString(b, "\n")
+		}
+		indent := strings.Repeat(" ", int(rules.LevelIndent)*entry.Level) //nolint: gosec
+		renderText(b, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, indent)
+
+		// entries are rendered through the same path as list items
+		item := &ItemElement{
+			IsOrdered: entry.Number != "",
+			Label:     entry.Number,
+		}
+		if err := item.Render(b, ctx); err != nil {
+			return err
+		}
+
+		el := &BaseElement{
+			Token: entry.Text,
+			Style: rules.Entry,
+		}
+		if err := el.Render(b, ctx); err != nil {
+			return err
+		}
+
+		if ctx.options.TOC.ShowIDs && entry.ID != "" {
+			el := &BaseElement{
+				Token:  "#" + entry.ID,
+				Prefix: " ",
+				Style:  rules.ID,
+			}
+			if err := el.Render(b, ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Finish finishes rendering a TOCElement.
+func (e *TOCElement) Finish(w io.Writer, ctx RenderContext) error {
+	return e.Block.Finish(w, ctx)
+}
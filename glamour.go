@@ -9,17 +9,28 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/muesli/termenv"
 	"github.com/yuin/goldmark"
 	emoji "github.com/yuin/goldmark-emoji"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 	"golang.org/x/term"
 
 	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/internal/alert"
+	"github.com/charmbracelet/glamour/internal/details"
+	"github.com/charmbracelet/glamour/internal/frontmatter"
+	"github.com/charmbracelet/glamour/internal/htmlinline"
+	"github.com/charmbracelet/glamour/internal/htmltable"
+	"github.com/charmbracelet/glamour/internal/spans"
+	"github.com/charmbracelet/glamour/internal/texmath"
+	"github.com/charmbracelet/glamour/internal/toc"
 	styles "github.com/charmbracelet/glamour/styles"
 )
 
@@ -35,8 +46,10 @@ type TermRendererOption func(*TermRenderer) error
 // customization and styles to fit your needs.
 type TermRenderer struct {
 	md               goldmark.Markdown
+	ar               *ansi.ANSIRenderer
 	ansiOptions      ansi.Options
 	kittyImageConfig *ansi.KittyImageConfig
+	palette          map[string]string
 	buf              bytes.Buffer
 	renderBuf        bytes.Buffer
 }
@@ -74,6 +87,11 @@ func NewTermRenderer(options ...TermRendererOption) (*TermRenderer, error) {
 			goldmark.WithExtensions(
 				extension.GFM,
 				extension.DefinitionList,
+				alert.New(),
+				frontmatter.New(),
+				htmltable.New(),
+				htmlinline.New(),
+				details.New(),
 			),
 			goldmark.WithParserOptions(
 				parser.WithAutoHeadingID(),
@@ -90,18 +108,23 @@ func NewTermRenderer(options ...TermRendererOption) (*TermRenderer, error) {
 		}
 	}
 
+	styles, err := ansi.ResolvePalette(tr.ansiOptions.Styles, tr.palette)
+	if err != nil {
+		return nil, err
+	}
+	tr.ansiOptions.Styles = styles
+
 	// Build list of node renderers based on configuration
 	nodeRenderers := []util.PrioritizedValue{}
 
 	// Add the standard ANSI renderer
 	// If kitty images are enabled, pass kitty config to renderer so ImageElement can output markers
-	var ar *ansi.ANSIRenderer
 	if tr.kittyImageConfig != nil && tr.kittyImageConfig.Enabled {
-		ar = ansi.NewRendererWithKitty(tr.ansiOptions, tr.kittyImageConfig)
+		tr.ar = ansi.NewRendererWithKitty(tr.ansiOptions, tr.kittyImageConfig)
 	} else {
-		ar = ansi.NewRenderer(tr.ansiOptions)
+		tr.ar = ansi.NewRenderer(tr.ansiOptions)
 	}
-	nodeRenderers = append(nodeRenderers, util.Prioritized(ar, highPriority))
+	nodeRenderers = append(nodeRenderers, util.Prioritized(tr.ar, highPriority))
 
 	tr.md.SetRenderer(
 		renderer.NewRenderer(
@@ -160,12 +183,7 @@ func WithStylePath(stylePath string) TermRendererOption {
 	return func(tr *TermRenderer) error {
 		styles, err := getDefaultStyle(stylePath)
 		if err != nil {
-			jsonBytes, err := os.ReadFile(stylePath)
-			if err != nil {
-				return fmt.Errorf("glamour: error reading file: %w", err)
-			}
-
-			return json.Unmarshal(jsonBytes, &tr.ansiOptions.Styles)
+			return withStyleFile(tr, stylePath)
 		}
 		tr.ansiOptions.Styles = *styles
 		return nil
@@ -181,24 +199,84 @@ func WithStyles(styles ansi.StyleConfig) TermRendererOption {
 }
 
 // WithStylesFromJSONBytes sets a TermRenderer's styles by parsing styles from
-// jsonBytes.
+// jsonBytes, replacing any styles set before. A style with an "extends" key,
+// naming a standard style or a style file, only needs to contain the settings
+// it changes. Use WithStyleOverlay to change the current styles instead.
 func WithStylesFromJSONBytes(jsonBytes []byte) TermRendererOption {
 	return func(tr *TermRenderer) error {
-		return json.Unmarshal(jsonBytes, &tr.ansiOptions.Styles)
+		s, err := styleFromJSON(ansi.StyleConfig{}, jsonBytes, "")
+		if err != nil {
+			return err
+		}
+		tr.ansiOptions.Styles = s
+		return nil
 	}
 }
 
 // WithStylesFromJSONFile sets a TermRenderer's styles from a JSON file.
+// Relative paths in its "extends" key are resolved against the file's
+// directory.
 func WithStylesFromJSONFile(filename string) TermRendererOption {
 	return func(tr *TermRenderer) error {
-		jsonBytes, err := os.ReadFile(filename)
-		if err != nil {
-			return fmt.Errorf("glamour: error reading file: %w", err)
+		return withStyleFile(tr, filename)
+	}
+}
+
+// WithPalette overrides colors of the style's palette, which its colors can
+// refer to as "$name". It recolors the standard styles and styles referring
+// to palette colors, whichever option sets them. Styles holding plain colors
+// only, such as styles.DarkStyleConfig, are left as is:
+//
+//	glamour.NewTermRenderer(
+//		glamour.WithStandardStyle(styles.DarkStyle),
+//		glamour.WithPalette(map[string]string{"accent": "#7D56F4"}),
+//	)
+func WithPalette(palette map[string]string) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		if tr.palette == nil {
+			tr.palette = make(map[string]string, len(palette))
+		}
+		for name, c := range palette {
+			tr.palette[name] = c
 		}
-		return json.Unmarshal(jsonBytes, &tr.ansiOptions.Styles)
+		return nil
 	}
 }
 
+// WithStyleOverlay merges JSON style fragments into a TermRenderer's styles,
+// in order. Each fragment only needs to contain the settings it changes, for
+// example to apply a brand's colors on top of a standard style:
+//
+//	glamour.NewTermRenderer(
+//		glamour.WithStandardStyle(styles.DarkStyle),
+//		glamour.WithStyleOverlay([]byte(`{"h1": {"background_color": "#7D56F4"}}`)),
+//	)
+func WithStyleOverlay(overlays ...[]byte) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		for _, o := range overlays {
+			s, err := styleFromJSON(tr.ansiOptions.Styles, o, "")
+			if err != nil {
+				return err
+			}
+			tr.ansiOptions.Styles = s
+		}
+		return nil
+	}
+}
+
+func withStyleFile(tr *TermRenderer, filename string) error {
+	jsonBytes, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("glamour: error reading file: %w", err)
+	}
+	s, err := styleFromJSON(ansi.StyleConfig{}, jsonBytes, filepath.Dir(filename))
+	if err != nil {
+		return err
+	}
+	tr.ansiOptions.Styles = s
+	return nil
+}
+
 // WithWordWrap sets a TermRenderer's word wrap.
 func WithWordWrap(wordWrap int) TermRendererOption {
 	return func(tr *TermRenderer) error {
@@ -231,6 +309,85 @@ func WithTableWrap(tableWrap bool) TermRendererOption {
 	}
 }
 
+// WithTableCards renders tables too wide for the terminal as stacked cards
+// instead, one per row, listing each cell next to its column's header. Tables
+// are only stacked if their natural width exceeds the available width by
+// more than threshold cells.
+func WithTableCards(threshold int) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.TableCards = true
+		tr.ansiOptions.TableCardThreshold = threshold
+		return nil
+	}
+}
+
+// WithTablePaging splits tables too wide for the terminal into pages of
+// columns that fit, each repeating the table's first column and captioned
+// with the range of columns it shows.
+func WithTablePaging(paging bool) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.TablePaging = paging
+		return nil
+	}
+}
+
+// WithTableNumbers detects table columns holding numbers only, right-aligns
+// them and styles their cells with the table style's number styles. Their
+// digits can be grouped in thousands and their decimal points lined up.
+func WithTableNumbers(opts ansi.TableNumbers) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.TableNumbers = &opts
+		return nil
+	}
+}
+
+// WithCollapsedDetails collapses <details> sections without an open
+// attribute, showing just their summary. RenderFolded can expand them.
+func WithCollapsedDetails() TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.CollapseDetails = true
+		return nil
+	}
+}
+
+// WithCodeOverflow sets how code lines exceeding the available width are
+// rendered. Long lines are left as is by default; they can be wrapped, with
+// continued lines marked in the gutter, or truncated with an ellipsis.
+func WithCodeOverflow(overflow ansi.CodeOverflow) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.CodeOverflow = overflow
+		return nil
+	}
+}
+
+// WithTabWidth expands tabs in code blocks to the given width instead of 8
+// columns.
+func WithTabWidth(width int) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.TabWidth = width
+		return nil
+	}
+}
+
+// WithLanguageDetection guesses the language of code blocks without one,
+// using it for syntax highlighting when confident enough. Language names are
+// resolved through aliases, e.g. "yml" to "yaml".
+func WithLanguageDetection(opts ansi.LanguageDetection) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.LanguageDetection = &opts
+		return nil
+	}
+}
+
+// WithStructuredData pretty-prints JSON code blocks, or draws JSON and YAML
+// code blocks as trees that collapse deep or long objects and arrays.
+func WithStructuredData(opts ansi.StructuredData) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.StructuredData = &opts
+		return nil
+	}
+}
+
 // WithInlineTableLinks forces tables to render links inline. By default,links
 // are rendered as a list of links at the bottom of the table.
 func WithInlineTableLinks(inlineTableLinks bool) TermRendererOption {
@@ -256,6 +413,57 @@ func WithEmoji() TermRendererOption {
 	}
 }
 
+// WithExtendedInlineSyntax enables ==highlighted==, ^superscript^,
+// ~subscript~, ++inserted++ and ||spoiler|| text. Single tildes no longer
+// mark strikethrough text.
+func WithExtendedInlineSyntax() TermRendererOption {
+	return func(tr *TermRenderer) error {
+		spans.New().Extend(tr.md)
+		return nil
+	}
+}
+
+// WithMath renders inline ($...$) and display ($$...$$) TeX math, converting
+// it to Unicode text. Display math is laid out over multiple lines and
+// centered.
+func WithMath() TermRendererOption {
+	return func(tr *TermRenderer) error {
+		texmath.New().Extend(tr.md)
+		return nil
+	}
+}
+
+// WithTableOfContents renders a table of contents in place of every [TOC]
+// placeholder paragraph. If the document has no placeholder, the table of
+// contents is rendered at the top of the document.
+func WithTableOfContents(opts ansi.TOCOptions) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.TOC = opts
+		toc.New().Extend(tr.md)
+		return nil
+	}
+}
+
+// WithHeadingNumbering prefixes headings with hierarchical section numbers
+// like "1.", "1.2" or "1.2.3". Heading styles with a format can place the
+// number themselves, e.g. "{{.number}} {{.text}}".
+func WithHeadingNumbering(numbering ansi.HeadingNumbering) TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.HeadingNumbering = &numbering
+		return nil
+	}
+}
+
+// WithFrontMatterHeader renders a document's front matter as a styled
+// metadata header showing its title, author, date and tags. By default front
+// matter is stripped from the output.
+func WithFrontMatterHeader() TermRendererOption {
+	return func(tr *TermRenderer) error {
+		tr.ansiOptions.FrontMatterHeader = true
+		return nil
+	}
+}
+
 // WithChromaFormatter sets a TermRenderer's chroma formatter used for code blocks.
 func WithChromaFormatter(formatter string) TermRendererOption {
 	return func(tr *TermRenderer) error {
@@ -320,6 +528,47 @@ func (tr *TermRenderer) RenderBytes(in []byte) ([]byte, error) {
 	return buf.Bytes(), err
 }
 
+// Result holds a rendered markdown document.
+type Result struct {
+	// Output is the rendered markdown.
+	Output []byte
+
+	// FrontMatter holds the document's parsed YAML or TOML front matter. It
+	// is nil if the document has none.
+	FrontMatter map[string]interface{}
+
+	// Folds holds the rendered <details> sections, with the output lines
+	// they span.
+	Folds []ansi.Fold
+}
+
+// RenderResult renders the markdown and returns it along with the document's
+// front matter and <details> sections.
+func (tr *TermRenderer) RenderResult(in []byte) (*Result, error) {
+	return tr.RenderFolded(in, nil)
+}
+
+// RenderFolded is like RenderResult, but expands or collapses <details>
+// sections according to folds. A pager can toggle a section by flipping its
+// entry and rendering again.
+func (tr *TermRenderer) RenderFolded(in []byte, folds ansi.Folds) (*Result, error) {
+	doc := tr.md.Parser().Parse(text.NewReader(in))
+
+	var buf bytes.Buffer
+	tr.ar.StartFolds(folds)
+	err := tr.md.Renderer().Render(&buf, in, doc)
+	out, found := tr.ar.FinishFolds(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("glamour: error rendering markdown: %w", err)
+	}
+
+	res := &Result{Output: out, Folds: found}
+	if d, ok := doc.(*ast.Document); ok {
+		res.FrontMatter = d.Meta()
+	}
+	return res, nil
+}
+
 func getEnvironmentStyle() string {
 	glamourStyle := os.Getenv("GLAMOUR_STYLE")
 	if len(glamourStyle) == 0 {
@@ -329,20 +578,112 @@ func getEnvironmentStyle() string {
 	return glamourStyle
 }
 
+// getDefaultStyle returns the template of a standard style, whose colors
+// refer to its palette, so a palette can recolor it.
 func getDefaultStyle(style string) (*ansi.StyleConfig, error) {
 	if style == styles.AutoStyle {
-		if !term.IsTerminal(int(os.Stdout.Fd())) {
-			return &styles.NoTTYStyleConfig, nil
-		}
-		if termenv.HasDarkBackground() {
-			return &styles.DarkStyleConfig, nil
+		switch {
+		case !term.IsTerminal(int(os.Stdout.Fd())):
+			style = styles.NoTTYStyle
+		case termenv.HasDarkBackground():
+			style = styles.DarkStyle
+		default:
+			style = styles.LightStyle
 		}
-		return &styles.LightStyleConfig, nil
 	}
 
-	styles, ok := styles.DefaultStyles[style]
+	styles, ok := styles.DefaultStyleTemplates[style]
 	if !ok {
 		return nil, fmt.Errorf("%s: style not found", style)
 	}
 	return styles, nil
 }
+
+// styleFromJSON applies the JSON style in jsonBytes on top of base. Objects
+// are merged key by key, so a style only needs to contain the settings it
+// changes. If it has an "extends" key, it is applied on top of the standard
+// style or style file named there instead of base. Relative style file paths
+// are resolved against dir.
+func styleFromJSON(base ansi.StyleConfig, jsonBytes []byte, dir string) (ansi.StyleConfig, error) {
+	return mergeStyleJSON(base, jsonBytes, dir, map[string]bool{})
+}
+
+func mergeStyleJSON(base ansi.StyleConfig, jsonBytes []byte, dir string, seen map[string]bool) (ansi.StyleConfig, error) {
+	var overlay map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &overlay); err != nil {
+		return base, fmt.Errorf("glamour: error parsing style: %w", err)
+	}
+
+	if extends, ok := overlay["extends"]; ok {
+		name, ok := extends.(string)
+		if !ok {
+			return base, fmt.Errorf("glamour: extends must be a style name or path, not %v", extends)
+		}
+		delete(overlay, "extends")
+
+		var err error
+		base, err = extendedStyle(name, dir, seen)
+		if err != nil {
+			return base, err
+		}
+	}
+
+	// round-trip the base style through JSON, so the merged style shares no
+	// pointers with it
+	b, err := json.Marshal(base)
+	if err != nil {
+		return base, fmt.Errorf("glamour: error encoding style: %w", err)
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(b, &merged); err != nil {
+		return base, fmt.Errorf("glamour: error encoding style: %w", err)
+	}
+	mergeJSON(merged, overlay)
+
+	b, err = json.Marshal(merged)
+	if err != nil {
+		return base, fmt.Errorf("glamour: error encoding style: %w", err)
+	}
+	var s ansi.StyleConfig
+	if err := json.Unmarshal(b, &s); err != nil {
+		return base, fmt.Errorf("glamour: error parsing style: %w", err)
+	}
+	return s, nil
+}
+
+// extendedStyle returns the style an "extends" key names: a standard style or
+// a style file.
+func extendedStyle(name, dir string, seen map[string]bool) (ansi.StyleConfig, error) {
+	if s, err := getDefaultStyle(name); err == nil {
+		return *s, nil
+	}
+
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if seen[path] {
+		return ansi.StyleConfig{}, fmt.Errorf("glamour: circular extends in style %s", path)
+	}
+	seen[path] = true
+
+	jsonBytes, err := os.ReadFile(path)
+	if err != nil {
+		return ansi.StyleConfig{}, fmt.Errorf("glamour: error reading file: %w", err)
+	}
+	return mergeStyleJSON(ansi.StyleConfig{}, jsonBytes, filepath.Dir(path), seen)
+}
+
+// mergeJSON merges src into dst. Objects are merged recursively, all other
+// values in src replace those in dst.
+func mergeJSON(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sv, ok := v.(map[string]interface{}); ok {
+			if dv, ok := dst[k].(map[string]interface{}); ok {
+				mergeJSON(dv, sv)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
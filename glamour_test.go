@@ -2,16 +2,22 @@ package glamour
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/glamour/ansi"
 	"github.com/charmbracelet/glamour/styles"
+	xansi "github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/golden"
+	"github.com/muesli/termenv"
 )
 
 const markdown = "testdata/readme.markdown.in"
@@ -325,3 +331,974 @@ func TestWithChromaFormatterCustom(t *testing.T) {
 
 	golden.RequireEqual(t, []byte(b))
 }
+
+func TestTableOfContents(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.AsciiStyle),
+		WithTableOfContents(ansi.TOCOptions{
+			MaxLevel: 3,
+			Numbered: true,
+			ShowIDs:  true,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := r.Render("# Runbook\n\n[TOC]\n\n## Install\n\n### Linux\n\n#### Skipped\n\n### macOS\n\n## Usage\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestTableOfContentsNumbering(t *testing.T) {
+	// the entries are numbered like the headings, from level 2, even though
+	// the table of contents lists level 1 as well
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.AsciiStyle),
+		WithTableOfContents(ansi.TOCOptions{
+			MaxLevel: 3,
+			Numbered: true,
+		}),
+		WithHeadingNumbering(ansi.HeadingNumbering{
+			StartLevel: 2,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := r.Render("# Runbook\n\n[TOC]\n\n## Install\n\n### Linux\n\n### macOS\n\n## Usage\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestHeadingNumbering(t *testing.T) {
+	style := styles.ASCIIStyleConfig
+	style.H4.Format = "{{.number}} - {{.text}}"
+
+	r, err := NewTermRenderer(
+		WithStyles(style),
+		WithHeadingNumbering(ansi.HeadingNumbering{
+			StartLevel: 2,
+			Formats: map[int]string{
+				4: "(%d)",
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := r.Render("# Title\n\n## Install\n\n### Linux\n\n#### Packages\n\n#### Source\n\n### macOS\n\n## Usage\n\n### Flags\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestAlerts(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.AsciiStyle),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := r.Render("> [!NOTE]\n> Useful information.\n\n> [!CAUTION] Data loss\n> Back up first.\n>\n> - twice\n\n> A regular quote.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestFrontMatter(t *testing.T) {
+	style := styles.ASCIIStyleConfig
+	style.H1.Format = "{{.text}} ({{.meta.version}})"
+
+	r, err := NewTermRenderer(
+		WithStyles(style),
+		WithFrontMatterHeader(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "---\ntitle: Release notes\nauthor: Jane Doe\ndate: 2024-01-02\nversion: 1.2.0\ntags: [go, cli]\n---\n\n# Changes\n\nFixed things.\n"
+	res, err := r.RenderResult([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if title := res.FrontMatter["title"]; title != "Release notes" {
+		t.Errorf("expected front matter title %q, got %v", "Release notes", title)
+	}
+
+	golden.RequireEqual(t, res.Output)
+}
+
+func TestFrontMatterNested(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithFrontMatterHeader(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "---\ntitle: Release notes\nauthor:\n  name: Jane Doe\n  email: jane@example.com\ntags:\n  - go\n  - name: cli\n---\n\nText.\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(b, "map[") {
+		t.Errorf("expected no nested values in %q", b)
+	}
+	for _, want := range []string{"Release notes", "Jane Doe", "go"} {
+		if !strings.Contains(b, want) {
+			t.Errorf("expected %q in %q", want, b)
+		}
+	}
+}
+
+func TestMath(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.AsciiStyle),
+		WithMath(),
+		WithWordWrap(60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := r.Render("Sorting takes $O(n \\log n)$ and costs \\$5.\n\n$$\\sum_{i=0}^n x_i = \\frac{n(n+1)}{2}$$\n\n$$\n\\begin{pmatrix} \\alpha & 0 \\\\ 0 & \\beta \\end{pmatrix}\n$$\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestMermaid(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.AsciiStyle),
+		WithWordWrap(60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "```mermaid\ngraph TD\n  A[Start] --> B{Ready?}\n  B -->|yes| C[Ship]\n  B -->|no| D(Fix)\n```\n\n" +
+		"```mermaid\nsequenceDiagram\n  Alice->>Bob: Hello\n  Bob-->>Alice: Hi\n```\n\n" +
+		"```mermaid\ngraph LR\n  A[This label is far too long to fit] --> B[Another label that is too long]\n```\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestCodeBlockAttributes(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.AsciiStyle),
+		WithWordWrap(60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "```go title=\"main.go\" {3-4} linenos\npackage main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n```\n\n" +
+		"```sh {linenos=true,linenostart=9}\necho one\necho two\n```\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestCodeBlockTabs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "highlight", in: "```go {2}\nfunc main() {\n\tprintln(\"hi\")\n}\n```\n"},
+		{name: "line numbers", in: "```go linenos\nfunc main() {\n\t\tprintln(\"hi\")\n}\n```\n"},
+		{name: "diff", in: "```diff\n func main() {\n-\tx := 1\n+\tx := 2\n }\n```\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewTermRenderer(
+				WithStandardStyle(styles.DarkStyle),
+				WithWordWrap(40),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := r.Render(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// tabs have no width of their own, so they must be expanded
+			if strings.Contains(b, "\t") {
+				t.Errorf("expected tabs to be expanded in %q", b)
+			}
+			for _, line := range strings.Split(b, "\n") {
+				if w := xansi.StringWidth(line); w > 40 {
+					t.Errorf("expected lines no wider than 40 columns, got %d: %q", w, line)
+				}
+			}
+		})
+	}
+}
+
+func TestCodeOverflow(t *testing.T) {
+	in := "```go linenos\nfunc main() {\n\tfmt.Println(\"this line is much too long to fit into the available width\")\n}\n```\n\n" +
+		"```\nplain text that is also much too long to fit into the available width\n```\n"
+
+	tests := []struct {
+		name     string
+		overflow ansi.CodeOverflow
+	}{
+		{name: "wrap", overflow: ansi.CodeOverflowWrap},
+		{name: "truncate", overflow: ansi.CodeOverflowTruncate},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewTermRenderer(
+				WithStandardStyle(styles.AsciiStyle),
+				WithWordWrap(50),
+				WithCodeOverflow(tc.overflow),
+				WithTabWidth(4),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := r.Render(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden.RequireEqual(t, []byte(b))
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.DarkStyle),
+		WithWordWrap(60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "```diff\ndiff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,5 +1,5 @@\n package main\n \n func main() {\n-\tx := 1\n+\tx := 2\n }\n```\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestInvalidBackgroundColors(t *testing.T) {
+	in := "```go {2}\npackage main\n\nfunc main() {}\n```\n\n" +
+		"```diff\n-\tx := 1\n+\tx := 2\n```\n\n" +
+		"| a | b |\n|---|---|\n| 1 | 2 |\n| 3 | 4 |\n"
+
+	for _, color := range []string{"darkgrey", ""} {
+		t.Run(color, func(t *testing.T) {
+			overlay := fmt.Sprintf(`{
+				"code_block": {
+					"highlight": {"background_color": %[1]q},
+					"diff": {"added": {"background_color": %[1]q}}
+				},
+				"table": {"row_backgrounds": [%[1]q, %[1]q]}
+			}`, color)
+			r, err := NewTermRenderer(
+				WithStandardStyle(styles.DarkStyle),
+				WithStyleOverlay([]byte(overlay)),
+				WithWordWrap(40),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Render(in); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestLanguageDetection(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStandardStyle(styles.DarkStyle),
+		WithWordWrap(60),
+		WithLanguageDetection(ansi.LanguageDetection{
+			Threshold: 0.5,
+			Aliases: map[string]string{
+				"conf": "ini",
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "    def add(a, b):\n        return a + b\n\n" +
+		"```\npackage main\n\nfunc main() {}\n```\n\n" +
+		"```conf\n[core]\neditor = vim\n```\n\n" +
+		"```\njust some words\n```\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestCodeBlockFrame(t *testing.T) {
+	style := styles.ASCIIStyleConfig
+	style.CodeBlock.Border = "ascii"
+
+	r, err := NewTermRenderer(
+		WithStyles(style),
+		WithWordWrap(40),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "```go\nfunc main() {}\n```\n\n" +
+		"```sh title=\"install.sh\" linenos\ngo install ./...\n```\n\n" +
+		"    no label\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestCSVTable(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithWordWrap(60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "```csv\nfruit,qty,price\napple,3,$1.20\n\"pear, green\",12,$0.80\n```\n\n" +
+		"```tsv header=false\nalpha\t1\nbeta\t22\n```\n\n" +
+		"```csv table=false\na,b\n```\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestStructuredData(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ansi.StructuredData
+	}{
+		{name: "pretty", opts: ansi.StructuredData{Pretty: true}},
+		{name: "tree", opts: ansi.StructuredData{Tree: true, MaxDepth: 2, MaxItems: 3}},
+	}
+
+	in := "```json\n{\"name\":\"glamour\",\"tags\":[\"go\",\"markdown\",\"tui\",\"cli\"],\"repo\":{\"owner\":{\"login\":\"charmbracelet\"},\"stars\":1200}}\n```\n\n" +
+		"```yaml\ntitle: Notes\ndraft: false\n```\n"
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewTermRenderer(
+				WithStyles(styles.ASCIIStyleConfig),
+				WithWordWrap(60),
+				WithStructuredData(tc.opts),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := r.Render(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden.RequireEqual(t, []byte(b))
+		})
+	}
+}
+
+func TestTableCards(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithWordWrap(40),
+		WithTableCards(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "| Name | Description | Version |\n|---|---|--:|\n" +
+		"| glamour | Stylesheet-based markdown rendering for your CLI apps | 1.0 |\n" +
+		"| lipgloss | Style definitions for nice terminal layouts | 2.1 |\n\n" +
+		"| a | b |\n|---|---|\n| fits | too |\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestTableStyles(t *testing.T) {
+	yes, no := true, false
+	border, header, column := "#626262", "#ffaf00", "#5fafff"
+	style := styles.ASCIIStyleConfig
+	style.Table.Border = "rounded"
+	style.Table.OuterBorder = &yes
+	style.Table.HeaderUnderline = &no
+	style.Table.BorderColor = &border
+	style.Table.Header = ansi.StylePrimitive{Bold: &yes, Color: &header}
+	style.Table.Columns = []ansi.StylePrimitive{{Color: &column}}
+	style.Table.RowBackgrounds = []string{"", "#303030"}
+
+	r, err := NewTermRenderer(
+		WithStyles(style),
+		WithWordWrap(40),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "| Name | Stars |\n|---|--:|\n| glamour | 2000 |\n| lipgloss | 9000 |\n| bubbles | 6000 |\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestTablePaging(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithWordWrap(40),
+		WithTablePaging(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "| Benchmark | ns/op | B/op | allocs/op | MB/s | Runs |\n|---|--:|--:|--:|--:|--:|\n" +
+		"| Render | 182345 | 90123 | 1534 | 12.5 | 6543 |\n" +
+		"| Wrap | 2345 | 512 | 8 | 401.2 | 512345 |\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestTableNumbers(t *testing.T) {
+	red := "#ff5f5f"
+	style := styles.ASCIIStyleConfig
+	style.Table.NegativeNumber = ansi.StylePrimitive{Color: &red}
+
+	r, err := NewTermRenderer(
+		WithStyles(style),
+		WithWordWrap(50),
+		WithTableNumbers(ansi.TableNumbers{
+			AlignDecimal:   true,
+			GroupSeparator: ",",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "| Account | Balance | Change |\n|---|---|---|\n" +
+		"| Checking | 12034.5 | 1.25% |\n" +
+		"| Savings | 250000 | -0.5% |\n" +
+		"| Credit | -1830.75 | 12% |\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestTableNumbersRowBackgrounds(t *testing.T) {
+	// numbers are formatted in the plain cells, then get the row's background
+	green := "#5fd787"
+	style := styles.ASCIIStyleConfig
+	style.Table.Number = ansi.StylePrimitive{Color: &green}
+	style.Table.RowBackgrounds = []string{"#303030", "#3a3a3a"}
+
+	tests := []struct {
+		name  string
+		width int
+		cards bool
+	}{
+		{name: "table", width: 50},
+		{name: "cards", width: 30, cards: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []TermRendererOption{
+				WithStyles(style),
+				WithWordWrap(tt.width),
+				WithTableNumbers(ansi.TableNumbers{
+					GroupSeparator: ",",
+				}),
+			}
+			if tt.cards {
+				opts = append(opts, WithTableCards(0))
+			}
+			r, err := NewTermRenderer(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			in := "| Account | Balance | Notes |\n|---|---|---|\n" +
+				"| Checking | 12034 | `main` account |\n" +
+				"| Savings | -250000 | **locked** |\n"
+			b, err := r.Render(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden.RequireEqual(t, []byte(b))
+		})
+	}
+}
+
+func TestHTMLTable(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithWordWrap(60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "<table>\n<thead>\n<tr><th>Package</th><th align=\"right\">Stars</th><th>Notes</th></tr>\n</thead>\n" +
+		"<tr><td><a href=\"https://github.com/charmbracelet/glamour\">glamour</a></td><td>2000</td><td><b>markdown</b> for the <code>terminal</code></td></tr>\n" +
+		"<tr><td colspan=\"2\">spans two columns</td><td><i>last</i></td></tr>\n" +
+		"\n" +
+		"<tr><td>after a blank line</td><td>1</td><td>x</td></tr>\n" +
+		"</table>\n\nAfter the table.\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestHTMLTags(t *testing.T) {
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithWordWrap(60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "<p align=\"center\">\n  <img src=\"logo.png\" alt=\"Logo\">\n  <br>A <b>bold</b> <a href=\"https://example.com\">project</a>\n</p>\n\n" +
+		"Press <kbd>Ctrl</kbd>+<kbd>C</kbd> to <mark>stop</mark>, <ins>added</ins> and <del>removed</del>.<br>\n" +
+		"H<sub>2</sub>O, x<sup>2</sup> and 10<sup>-3</sup>, a<sub>max</sub>.\n\n" +
+		"<details>\n<summary>More <i>details</i></summary>\n\nHidden *content*.\n\n</details>\n"
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden.RequireEqual(t, []byte(b))
+}
+
+func TestHTMLBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "pre",
+			in:   "<pre>\nfunc main() {\n    fmt.Println(\"hi\")\n}\n</pre>\n",
+		},
+		{
+			name: "lists",
+			in:   "<ul>\n<li>one</li>\n<li>two\n<ol start=\"3\"><li>three</li><li>four</li></ol></li>\n</ul>\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewTermRenderer(
+				WithStyles(styles.ASCIIStyleConfig),
+				WithWordWrap(40),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := r.Render(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden.RequireEqual(t, []byte(b))
+		})
+	}
+}
+
+func TestDetails(t *testing.T) {
+	tests := []struct {
+		name  string
+		folds ansi.Folds
+		want  []ansi.Fold
+	}{
+		{
+			name: "collapsed",
+			want: []ansi.Fold{
+				{Index: 0, Summary: "Build log", StartLine: 3, EndLine: 3},
+				{Index: 2, ID: "notes", Summary: "Notes", Expanded: true, StartLine: 5, EndLine: 6},
+			},
+		},
+		{
+			name:  "expanded",
+			folds: ansi.Folds{"0": true, "1": true, "notes": false},
+			want: []ansi.Fold{
+				{Index: 0, Summary: "Build log", Expanded: true, StartLine: 3, EndLine: 10},
+				{Index: 1, Summary: "Details", Expanded: true, StartLine: 7, EndLine: 8},
+				{Index: 2, ID: "notes", Summary: "Notes", StartLine: 12, EndLine: 12},
+			},
+		},
+	}
+
+	in := "Intro.\n\n<details>\n<summary>Build <code>log</code></summary>\n\n```\nline 1\nline 2\n```\n\n" +
+		"<details>\n\nNested.\n\n</details>\n\nMore *text*.\n\n</details>\n\n" +
+		"<details id=\"notes\" open><summary>Notes</summary>Always shown.</details>\n\nEnd.\n"
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewTermRenderer(
+				WithStyles(styles.ASCIIStyleConfig),
+				WithWordWrap(40),
+				WithCollapsedDetails(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := r.RenderFolded([]byte(in), tc.folds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res.Folds, tc.want) {
+				t.Errorf("expected folds %+v, got %+v", tc.want, res.Folds)
+			}
+
+			golden.RequireEqual(t, res.Output)
+		})
+	}
+
+	// without a fold map, rendering is the same as with Render
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithCollapsedDetails(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := r.RenderResult([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res.Output) != out {
+		t.Errorf("expected RenderResult output %q, got %q", out, res.Output)
+	}
+}
+
+func TestExtendedInlineSyntax(t *testing.T) {
+	in := "==Highlighted== and ++inserted++ text, x^2^ + x^q^, H~2~O and a~max~, " +
+		"but ~~struck~~ and ^not closed.\n\nThe butler did it: ||*spoiler*||.\n"
+
+	r, err := NewTermRenderer(
+		WithStyles(styles.ASCIIStyleConfig),
+		WithWordWrap(60),
+		WithExtendedInlineSyntax(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.RequireEqual(t, []byte(b))
+
+	// spoilers are concealed in color styles
+	r, err = NewTermRenderer(
+		WithStyles(styles.DarkStyleConfig),
+		WithExtendedInlineSyntax(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = r.Render("||secret||")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b, ";8msecret") {
+		t.Errorf("expected concealed text, got %q", b)
+	}
+}
+
+func TestStylePrimitives(t *testing.T) {
+	yes := true
+	text, prefix := "#ffffff", "#ff0000"
+	style := styles.ASCIIStyleConfig
+	style.Heading.Color = &text
+	style.Heading.PrefixColor = &prefix
+	style.Emph = ansi.StylePrimitive{Faint: &yes}
+	style.Strong = ansi.StylePrimitive{Conceal: &yes}
+
+	r, err := NewTermRenderer(WithStyles(style))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.Render("## Title\n\n*faint* and **hidden**\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"\x1b[38;2;255;0;0m## \x1b[0m",
+		"\x1b[38;2;255;255;255mTitle",
+		"\x1b[2mfaint\x1b[0m",
+		"\x1b[8mhidden\x1b[0m",
+	} {
+		if !strings.Contains(b, want) {
+			t.Errorf("expected %q in %q", want, b)
+		}
+	}
+}
+
+func TestUnderlineStyles(t *testing.T) {
+	in := "A <u>mispelled</u> word\n"
+	for _, tc := range []struct {
+		profile termenv.Profile
+		want    string
+	}{
+		{termenv.TrueColor, ";4:3;58;5;203mmispelled"},
+		// styled and colored underlines degrade to plain ones
+		{termenv.ANSI256, ";4mmispelled"},
+	} {
+		r, err := NewTermRenderer(
+			WithStyles(styles.DarkStyleConfig),
+			WithColorProfile(tc.profile),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := r.Render(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b, tc.want) {
+			t.Errorf("expected %q in %q", tc.want, b)
+		}
+	}
+}
+
+func TestStylesFromJSONReplace(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "style.json")
+	if err := os.WriteFile(file, []byte(`{"document": {"margin": 1}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		option TermRendererOption
+	}{
+		{name: "bytes", option: WithStylesFromJSONBytes([]byte(`{"document": {"margin": 1}}`))},
+		{name: "file", option: WithStylesFromJSONFile(file)},
+		{name: "path", option: WithStylePath(file)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewTermRenderer(WithStandardStyle(styles.DarkStyle), tc.option)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := r.ansiOptions.Styles
+			if s.Document.Margin == nil || *s.Document.Margin != 1 {
+				t.Errorf("expected the style's margin, got %v", s.Document.Margin)
+			}
+			if s.H1.Prefix != "" || s.Link.Color != nil {
+				t.Error("expected the style to replace the dark style")
+			}
+		})
+	}
+}
+
+func TestStyleExtends(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("base.json", `{"extends": "dark", "h1": {"prefix": "> "}}`)
+	brand := write("brand.json", `{"extends": "base.json", "h1": {"background_color": "#7D56F4"}}`)
+
+	r, err := NewTermRenderer(
+		WithStylesFromJSONFile(brand),
+		WithStyleOverlay([]byte(`{"link": {"color": "#FF0000"}}`), []byte(`{"h2": {"bold": false}}`)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := styles.DarkStyleConfig
+	prefix, bg, link, no := "> ", "#7D56F4", "#FF0000", false
+	want.H1.Prefix = prefix
+	want.H1.BackgroundColor = &bg
+	want.Link.Color = &link
+	want.H2.Bold = &no
+	if got := r.ansiOptions.Styles; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the dark style with overrides, got %+v", got)
+	}
+	if *styles.DarkStyleConfig.H1.BackgroundColor == bg || *styles.DarkStyleConfig.Link.Color == link {
+		t.Error("overlay modified the dark style")
+	}
+
+	loop := write("loop.json", `{"extends": "loop.json"}`)
+	if _, err := NewTermRenderer(WithStylesFromJSONFile(loop)); err == nil {
+		t.Error("expected an error for a style extending itself")
+	}
+}
+
+func TestPalette(t *testing.T) {
+	in := "# Title\n\nSee [glamour](https://github.com/charmbracelet/glamour).\n"
+
+	// recoloring a standard style
+	r, err := NewTermRenderer(
+		WithPalette(map[string]string{"accent": "#7D56F4", "link": "#FF0000"}),
+		WithStandardStyle(styles.DarkStyle),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.Render(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"48;2;125;86;243", "38;2;255;0;0;4mhttps://"} {
+		if !strings.Contains(b, want) {
+			t.Errorf("expected %q in %q", want, b)
+		}
+	}
+
+	// extending a standard style
+	r, err = NewTermRenderer(WithStylesFromJSONBytes([]byte(
+		`{"extends": "dark", "palette": {"accent": "#7D56F4"}}`,
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := r.ansiOptions.Styles.H1.BackgroundColor; c == nil || *c != "#7D56F4" {
+		t.Errorf("expected the palette color, got %v", c)
+	}
+
+	// the standard styles' colors are all in their palettes
+	for name, s := range styles.DefaultStyleTemplates {
+		if _, err := ansi.ResolvePalette(*s, nil); err != nil {
+			t.Errorf("%s style: %v", name, err)
+		}
+	}
+
+	// the exported styles hold plain colors
+	for name, s := range styles.DefaultStyles {
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(b), `"$`) {
+			t.Errorf("expected no palette references in the %s style", name)
+		}
+	}
+
+	// palettes in style files
+	r, err = NewTermRenderer(WithStylesFromJSONBytes([]byte(
+		`{"palette": {"brand": "#00FF00"}, "document": {"color": "$brand"}}`,
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := r.ansiOptions.Styles.Document.Color; c == nil || *c != "#00FF00" {
+		t.Errorf("expected the palette color, got %v", c)
+	}
+
+	if _, err := NewTermRenderer(WithStylesFromJSONBytes([]byte(
+		`{"document": {"color": "$missing"}}`,
+	))); err == nil {
+		t.Error("expected an error for a color missing from the palette")
+	}
+}
+
+func TestHeadingFormat(t *testing.T) {
+	style := styles.DarkStyleConfig
+	style.H2.Format = "[ {{.text}} ]"
+
+	r, err := NewTermRenderer(WithStyles(style))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.Render("## Hello `world`\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the format's text gets the heading's style, while the code span keeps
+	// its own
+	for _, want := range []string{
+		"## \x1b[0m\x1b[38;5;39;1m[ ",
+		"\x1b[38;5;203;48;5;236;1m world \x1b[0m",
+		"\x1b[0m\x1b[38;5;39;1m ]\x1b[0m",
+	} {
+		if !strings.Contains(b, want) {
+			t.Errorf("expected %q in %q", want, b)
+		}
+	}
+}
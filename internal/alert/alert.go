@@ -0,0 +1,141 @@
+// Package alert provides a goldmark extension for GitHub-style alerts, i.e.
+// blockquotes starting with a marker like [!NOTE] or [!WARNING].
+package alert
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Alert types supported by GitHub.
+const (
+	Note      = "note"
+	Tip       = "tip"
+	Important = "important"
+	Warning   = "warning"
+	Caution   = "caution"
+)
+
+// KindAlert is the NodeKind of an Alert node.
+var KindAlert = ast.NewNodeKind("Alert")
+
+// Alert is a blockquote that has been marked as an alert.
+type Alert struct {
+	ast.BaseBlock
+
+	// AlertType is the lower-cased alert type, e.g. "note".
+	AlertType string
+
+	// Title is an optional custom title following the marker.
+	Title string
+}
+
+// Kind implements ast.Node.Kind.
+func (n *Alert) Kind() ast.NodeKind {
+	return KindAlert
+}
+
+// Dump implements ast.Node.Dump.
+func (n *Alert) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"AlertType": n.AlertType,
+		"Title":     n.Title,
+	}, nil)
+}
+
+// NewAlert returns a new Alert node.
+func NewAlert(alertType, title string) *Alert {
+	return &Alert{
+		AlertType: alertType,
+		Title:     title,
+	}
+}
+
+var markerRe = regexp.MustCompile(`^\[!([A-Za-z]+)\][ \t]*(.*)$`)
+
+// parseMarker returns the alert type and optional title if line starts with
+// an alert marker.
+func parseMarker(line []byte) (string, string, bool) {
+	m := markerRe.FindSubmatch(bytes.TrimSpace(line))
+	if m == nil {
+		return "", "", false
+	}
+
+	alertType := strings.ToLower(string(m[1]))
+	switch alertType {
+	case Note, Tip, Important, Warning, Caution:
+		return alertType, string(m[2]), true
+	}
+	return "", "", false
+}
+
+type transformer struct{}
+
+// Transform replaces blockquotes starting with an alert marker with Alert
+// nodes and strips the marker line.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var quotes []*ast.Blockquote
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if q, ok := n.(*ast.Blockquote); ok && entering {
+			quotes = append(quotes, q)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, q := range quotes {
+		p := q.FirstChild()
+		if p == nil || p.Kind() != ast.KindParagraph || p.Lines().Len() == 0 {
+			continue
+		}
+		first := p.Lines().At(0)
+		alertType, title, ok := parseMarker(first.Value(source))
+		if !ok {
+			continue
+		}
+
+		// drop the inline nodes making up the marker line
+		for c := p.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			if t, ok := c.(*ast.Text); ok && t.Segment.Start >= first.Stop {
+				break
+			}
+			p.RemoveChild(p, c)
+			c = next
+		}
+		if !p.HasChildren() {
+			q.RemoveChild(q, p)
+		}
+
+		a := NewAlert(alertType, title)
+		for c := q.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			a.AppendChild(a, c)
+			c = next
+		}
+		q.Parent().ReplaceChild(q.Parent(), q, a)
+	}
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that turns blockquotes starting with an
+// alert marker into Alert nodes.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&transformer{}, 100), //nolint: mnd
+	))
+}
@@ -0,0 +1,54 @@
+package alert_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/alert"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestAlert(t *testing.T) {
+	tests := []struct {
+		in       string
+		alert    bool
+		typ      string
+		title    string
+		children int
+	}{
+		{"> [!NOTE]\n> Some text.", true, alert.Note, "", 1},
+		{"> [!warning] Careful\n> Some text.\n>\n> More.", true, alert.Warning, "Careful", 2},
+		{"> [!TIP]", true, alert.Tip, "", 0},
+		{"> [!UNKNOWN]\n> Some text.", false, "", "", 0},
+		{"> Just a quote.", false, "", "", 0},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(alert.New()))
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tc.in)))
+			n := doc.FirstChild()
+
+			a, ok := n.(*alert.Alert)
+			if ok != tc.alert {
+				t.Fatalf("expected alert %v, got %s", tc.alert, n.Kind())
+			}
+			if !ok {
+				if n.Kind() != ast.KindBlockquote {
+					t.Fatalf("expected blockquote, got %s", n.Kind())
+				}
+				return
+			}
+			if a.AlertType != tc.typ {
+				t.Errorf("expected type %q, got %q", tc.typ, a.AlertType)
+			}
+			if a.Title != tc.title {
+				t.Errorf("expected title %q, got %q", tc.title, a.Title)
+			}
+			if a.ChildCount() != tc.children {
+				t.Errorf("expected %d children, got %d", tc.children, a.ChildCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package csvtable parses CSV and TSV data into a table, detecting whether
+// the first record is a header and which columns hold numbers.
+package csvtable
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/glamour/internal/numfmt"
+)
+
+// ErrEmpty is returned when the data holds no records.
+var ErrEmpty = errors.New("csvtable: no records")
+
+// Header controls how the first record is treated.
+type Header int
+
+// Header modes.
+const (
+	// HeaderAuto guesses whether the first record is a header.
+	HeaderAuto Header = iota
+	// HeaderOn always treats the first record as a header.
+	HeaderOn
+	// HeaderOff never treats the first record as a header.
+	HeaderOff
+)
+
+// Table is a parsed CSV or TSV table. All rows have the same number of
+// cells.
+type Table struct {
+	// Header holds the column names, or nil if the data has no header.
+	Header []string
+
+	// Rows holds the body of the table.
+	Rows [][]string
+
+	// Numeric reports for each column whether all of its non-empty body
+	// cells are numbers.
+	Numeric []bool
+}
+
+// Columns returns the number of columns in the table.
+func (t *Table) Columns() int {
+	return len(t.Numeric)
+}
+
+// Parse parses data separated by comma, e.g. ',' for CSV or '\t' for TSV.
+// TSV fields can't be quoted, so quotes are kept as they are. Records with
+// fewer fields than the widest record are padded with empty cells.
+func Parse(data string, comma rune, header Header) (*Table, error) {
+	records, err := read(data, comma)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrEmpty
+	}
+
+	var cols int
+	for _, rec := range records {
+		cols = max(cols, len(rec))
+	}
+	for i, rec := range records {
+		for j := range rec {
+			rec[j] = strings.Join(strings.Fields(rec[j]), " ")
+		}
+		for len(rec) < cols {
+			rec = append(rec, "")
+		}
+		records[i] = rec
+	}
+
+	t := &Table{Rows: records}
+	if header == HeaderOn || (header == HeaderAuto && isHeader(records)) {
+		t.Header = records[0]
+		t.Rows = records[1:]
+	}
+	t.Numeric = numericColumns(t.Rows, cols)
+	return t, nil
+}
+
+func read(data string, comma rune) ([][]string, error) {
+	if comma == '\t' {
+		var records [][]string
+		for _, line := range strings.Split(data, "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			records = append(records, strings.Split(line, "\t"))
+		}
+		return records, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("csvtable: %w", err)
+	}
+	return records, nil
+}
+
+// isHeader guesses whether the first record is a header: its cells must be
+// non-empty, unique and not numbers, and there must be a body beneath it.
+func isHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return false
+	}
+	seen := map[string]bool{}
+	for _, c := range records[0] {
+		if c == "" || numfmt.IsNumber(c) || seen[c] {
+			return false
+		}
+		seen[c] = true
+	}
+	return true
+}
+
+func numericColumns(rows [][]string, cols int) []bool {
+	numeric := make([]bool, cols)
+	for col := range numeric {
+		var n int
+		numeric[col] = true
+		for _, row := range rows {
+			if row[col] == "" {
+				continue
+			}
+			if !numfmt.IsNumber(row[col]) {
+				numeric[col] = false
+				break
+			}
+			n++
+		}
+		numeric[col] = numeric[col] && n > 0
+	}
+	return numeric
+}
@@ -0,0 +1,96 @@
+package csvtable_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/csvtable"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		comma   rune
+		header  csvtable.Header
+		head    []string
+		rows    [][]string
+		numeric []bool
+	}{
+		{
+			name:    "header detected",
+			data:    "name,qty,price\napple,3,1.20\npear,12,0.80\n",
+			comma:   ',',
+			head:    []string{"name", "qty", "price"},
+			rows:    [][]string{{"apple", "3", "1.20"}, {"pear", "12", "0.80"}},
+			numeric: []bool{false, true, true},
+		},
+		{
+			name:    "numeric first row",
+			data:    "1,2\n3,4\n",
+			comma:   ',',
+			rows:    [][]string{{"1", "2"}, {"3", "4"}},
+			numeric: []bool{true, true},
+		},
+		{
+			name:    "header forced off",
+			data:    "a,b\nc,d\n",
+			comma:   ',',
+			header:  csvtable.HeaderOff,
+			rows:    [][]string{{"a", "b"}, {"c", "d"}},
+			numeric: []bool{false, false},
+		},
+		{
+			name:    "header forced on",
+			data:    "2023,2024\n1,2\n",
+			comma:   ',',
+			header:  csvtable.HeaderOn,
+			head:    []string{"2023", "2024"},
+			rows:    [][]string{{"1", "2"}},
+			numeric: []bool{true, true},
+		},
+		{
+			name:    "tsv with ragged rows",
+			data:    "id\tnote\n1\t\"quoted\" text\n2\n",
+			comma:   '\t',
+			head:    []string{"id", "note"},
+			rows:    [][]string{{"1", "\"quoted\" text"}, {"2", ""}},
+			numeric: []bool{true, false},
+		},
+		{
+			name:    "quoted multiline cell",
+			data:    "k,v\nx,\"a\nb\"\n",
+			comma:   ',',
+			head:    []string{"k", "v"},
+			rows:    [][]string{{"x", "a b"}},
+			numeric: []bool{false, false},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tbl, err := csvtable.Parse(tc.data, tc.comma, tc.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tbl.Header, tc.head) {
+				t.Errorf("header: expected %q, got %q", tc.head, tbl.Header)
+			}
+			if !reflect.DeepEqual(tbl.Rows, tc.rows) {
+				t.Errorf("rows: expected %q, got %q", tc.rows, tbl.Rows)
+			}
+			if !reflect.DeepEqual(tbl.Numeric, tc.numeric) {
+				t.Errorf("numeric: expected %v, got %v", tc.numeric, tbl.Numeric)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := csvtable.Parse("", ',', csvtable.HeaderAuto); err != csvtable.ErrEmpty {
+		t.Errorf("expected ErrEmpty, got %v", err)
+	}
+	if _, err := csvtable.Parse("a,\"b\n", ',', csvtable.HeaderAuto); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
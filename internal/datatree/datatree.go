@@ -0,0 +1,137 @@
+// Package datatree draws decoded JSON or YAML values as a tree with
+// box-drawing guides, collapsing deeply nested or long objects and arrays.
+package datatree
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Options configures how a tree is drawn.
+type Options struct {
+	// MaxDepth collapses objects and arrays nested deeper than MaxDepth
+	// levels below the root. Zero means no limit.
+	MaxDepth int
+
+	// MaxItems shows at most MaxItems entries of an object or array,
+	// followed by a "… N more items" line. Zero means no limit.
+	MaxItems int
+
+	// Width truncates lines wider than Width cells. Zero means no limit.
+	Width int
+}
+
+// Guides used to draw the tree.
+const (
+	branch = "├── "
+	last   = "└── "
+	pipe   = "│   "
+	space  = "    "
+)
+
+// Render draws v, which holds values as decoded by encoding/json: objects as
+// map[string]interface{} and arrays as []interface{}. Object keys are sorted.
+func Render(v interface{}, opts Options) []string {
+	t := &tree{opts: opts}
+	t.node("", v, "", "", 0)
+	return t.lines
+}
+
+type tree struct {
+	opts  Options
+	lines []string
+}
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+func (t *tree) add(s string) {
+	if t.opts.Width > 0 {
+		s = ansi.Truncate(s, t.opts.Width, "…")
+	}
+	t.lines = append(t.lines, s)
+}
+
+// node draws a value and its children. prefix is the guide drawn in
+// front of the value's own line, indent the guide in front of its children.
+func (t *tree) node(key string, v interface{}, prefix, indent string, depth int) {
+	line := prefix + key
+	if key != "" {
+		line += ": "
+	}
+	summary := label(v)
+	entries := children(v)
+	if len(entries) == 0 {
+		t.add(line + summary)
+		return
+	}
+	if t.opts.MaxDepth > 0 && depth >= t.opts.MaxDepth {
+		t.add(line + summary + " …")
+		return
+	}
+	t.add(line + summary)
+
+	shown := entries
+	if t.opts.MaxItems > 0 && len(entries) > t.opts.MaxItems {
+		shown = entries[:t.opts.MaxItems]
+	}
+	for i, e := range shown {
+		p, ind := branch, pipe
+		if i == len(entries)-1 {
+			p, ind = last, space
+		}
+		t.node(e.key, e.value, indent+p, indent+ind, depth+1)
+	}
+	if more := len(entries) - len(shown); more > 0 {
+		items := "items"
+		if more == 1 {
+			items = "item"
+		}
+		t.add(fmt.Sprintf("%s%s… %d more %s", indent, last, more, items))
+	}
+}
+
+// children returns the entries of an object or array, or nil for scalars.
+func children(v interface{}) []entry {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		entries := make([]entry, 0, len(v))
+		for _, k := range keys {
+			entries = append(entries, entry{key: k, value: v[k]})
+		}
+		return entries
+	case []interface{}:
+		entries := make([]entry, 0, len(v))
+		for i, e := range v {
+			entries = append(entries, entry{key: "[" + strconv.Itoa(i) + "]", value: e})
+		}
+		return entries
+	}
+	return nil
+}
+
+// label returns the text drawn for a value: the size of objects and arrays,
+// or the scalar itself.
+func label(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return "{" + strconv.Itoa(len(v)) + "}"
+	case []interface{}:
+		return "[" + strconv.Itoa(len(v)) + "]"
+	case string:
+		return strconv.Quote(v)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
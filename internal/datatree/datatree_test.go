@@ -0,0 +1,74 @@
+package datatree_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/datatree"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		opts datatree.Options
+		want string
+	}{
+		{
+			name: "scalar",
+			json: `"hello"`,
+			want: `"hello"`,
+		},
+		{
+			name: "nested",
+			json: `{"name":"glamour","tags":["go","md"],"meta":{"stars":1,"private":false,"license":null}}`,
+			want: `{3}
+├── meta: {3}
+│   ├── license: null
+│   ├── private: false
+│   └── stars: 1
+├── name: "glamour"
+└── tags: [2]
+    ├── [0]: "go"
+    └── [1]: "md"`,
+		},
+		{
+			name: "max depth",
+			json: `{"a":{"b":{"c":1}},"d":[]}`,
+			opts: datatree.Options{MaxDepth: 1},
+			want: `{2}
+├── a: {1} …
+└── d: [0]`,
+		},
+		{
+			name: "max items",
+			json: `[1,2,3,4,5]`,
+			opts: datatree.Options{MaxItems: 2},
+			want: `[5]
+├── [0]: 1
+├── [1]: 2
+└── … 3 more items`,
+		},
+		{
+			name: "width",
+			json: `{"description":"a rather long string value"}`,
+			opts: datatree.Options{Width: 24},
+			want: `{1}
+└── description: "a rat…`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(tc.json), &v); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Join(datatree.Render(v, tc.opts), "\n")
+			if got != tc.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Package details provides a goldmark extension that wraps HTML <details>
+// elements, and the Markdown between their opening and closing tags, in
+// Details nodes.
+package details
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/net/html"
+)
+
+// KindDetails is the NodeKind of a Details node.
+var KindDetails = ast.NewNodeKind("Details")
+
+// Details is a block node holding a <details> element. Its first child is
+// the HTML block opening the element, and its last child the one closing it,
+// if they differ.
+type Details struct {
+	ast.BaseBlock
+
+	// Index is the element's zero-based position in the document.
+	Index int
+
+	// ID is the element's id attribute.
+	ID string
+
+	// Summary is the inner HTML of the element's <summary>.
+	Summary string
+
+	// Open is set if the element has an open attribute.
+	Open bool
+}
+
+// Kind implements ast.Node.Kind.
+func (n *Details) Kind() ast.NodeKind {
+	return KindDetails
+}
+
+// Dump implements ast.Node.Dump.
+func (n *Details) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Key":     n.Key(),
+		"Summary": n.Summary,
+	}, nil)
+}
+
+// Key returns the key identifying the element: its ID if it has one, its
+// index otherwise.
+func (n *Details) Key() string {
+	if n.ID != "" {
+		return n.ID
+	}
+	return strconv.Itoa(n.Index)
+}
+
+var (
+	openRe    = regexp.MustCompile(`(?i)<details[\s>]`)
+	closeRe   = regexp.MustCompile(`(?i)</details\s*>`)
+	summaryRe = regexp.MustCompile(`(?is)<summary[^>]*>(.*?)</summary\s*>`)
+)
+
+// blockHTML returns the source of an HTML block.
+func blockHTML(n *ast.HTMLBlock, source []byte) string {
+	var b strings.Builder
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		b.Write(line.Value(source))
+	}
+	if n.HasClosure() {
+		b.Write(n.ClosureLine.Value(source))
+	}
+	return b.String()
+}
+
+// depth returns the number of <details> elements s opens, minus the number
+// it closes.
+func depth(s string) int {
+	return len(openRe.FindAllStringIndex(s, -1)) - len(closeRe.FindAllStringIndex(s, -1))
+}
+
+// newDetails returns a Details node for an HTML block opening a <details>
+// element, reporting false if it doesn't open one.
+func newDetails(src string) (*Details, bool) {
+	if loc := openRe.FindStringIndex(strings.TrimSpace(src)); loc == nil || loc[0] != 0 {
+		return nil, false
+	}
+
+	d := &Details{Summary: "Details"}
+	z := html.NewTokenizer(strings.NewReader(src))
+	for tt := z.Next(); tt != html.ErrorToken; tt = z.Next() {
+		tok := z.Token()
+		if tok.Data != "details" {
+			continue
+		}
+		for _, a := range tok.Attr {
+			switch a.Key {
+			case "id":
+				d.ID = a.Val
+			case "open":
+				d.Open = true
+			}
+		}
+		break
+	}
+	if m := summaryRe.FindStringSubmatch(src); m != nil {
+		d.Summary = strings.TrimSpace(m[1])
+	}
+	return d, true
+}
+
+type transformer struct{}
+
+// Transform wraps <details> elements in Details nodes and numbers them in
+// document order. Elements without a closing tag are left as they are.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	wrap(doc, reader.Source())
+
+	index := 0
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if d, ok := n.(*Details); ok && entering {
+			d.Index = index
+			index++
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func wrap(parent ast.Node, source []byte) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		if _, ok := parent.(*Details); ok && n == parent.FirstChild() {
+			// the block opening parent
+			continue
+		}
+		if b, ok := n.(*ast.HTMLBlock); ok {
+			if d := wrapDetails(parent, b, source); d != nil {
+				n = d
+			}
+		}
+		if n.Type() == ast.TypeBlock && n.HasChildren() {
+			wrap(n, source)
+		}
+	}
+}
+
+// wrapDetails replaces the HTML block opening a <details> element, and its
+// siblings up to the block closing it, with a Details node. It returns nil
+// if the block doesn't open an element.
+func wrapDetails(parent ast.Node, start *ast.HTMLBlock, source []byte) ast.Node {
+	src := blockHTML(start, source)
+	d, ok := newDetails(src)
+	if !ok {
+		return nil
+	}
+
+	// find the block closing the element
+	end := ast.Node(start)
+	for level := depth(src); level > 0; {
+		end = end.NextSibling()
+		if end == nil {
+			return nil
+		}
+		if b, ok := end.(*ast.HTMLBlock); ok {
+			level += depth(blockHTML(b, source))
+		}
+	}
+
+	parent.ReplaceChild(parent, start, d)
+	d.AppendChild(d, start)
+	for n := d.NextSibling(); n != nil && start != end; {
+		next := n.NextSibling()
+		d.AppendChild(d, n)
+		if n == end {
+			break
+		}
+		n = next
+	}
+	return d
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that wraps <details> elements in Details
+// nodes.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&transformer{}, 100), //nolint: mnd
+	))
+}
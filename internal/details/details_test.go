@@ -0,0 +1,55 @@
+package details_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/details"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestTransform(t *testing.T) {
+	in := "<details>\n<summary>First <b>one</b></summary>\n\nBody.\n\n" +
+		"<details id=\"inner\" open>\n<summary>Inner</summary>\n\nDeep.\n\n</details>\n\n" +
+		"</details>\n\n" +
+		"<details><summary>Inline</summary>Body</details>\n\n" +
+		"<details>\n\nNo summary.\n\n</details>\n\n" +
+		"<details>\n<summary>Unclosed</summary>\n\nText.\n"
+
+	md := goldmark.New(goldmark.WithExtensions(details.New()))
+	doc := md.Parser().Parse(text.NewReader([]byte(in)))
+
+	var got []*details.Details
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if d, ok := n.(*details.Details); ok && entering {
+			got = append(got, d)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	want := []struct {
+		key      string
+		summary  string
+		open     bool
+		children int
+	}{
+		{"0", "First <b>one</b>", false, 4},
+		{"inner", "Inner", true, 3},
+		{"2", "Inline", false, 1},
+		{"3", "Details", false, 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d details, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		d := got[i]
+		if d.Index != i || d.Key() != w.key || d.Summary != w.summary || d.Open != w.open {
+			t.Errorf("details %d: expected key %q, summary %q, open %v, got index %d, key %q, summary %q, open %v",
+				i, w.key, w.summary, w.open, d.Index, d.Key(), d.Summary, d.Open)
+		}
+		if n := d.ChildCount(); n != w.children {
+			t.Errorf("details %d: expected %d children, got %d", i, w.children, n)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Package diagram renders Mermaid flowcharts and sequence diagrams as
+// box-drawing art on a character grid.
+package diagram
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	// ErrUnsupported is returned for diagram types that can't be rendered.
+	ErrUnsupported = errors.New("diagram: unsupported diagram type")
+
+	// ErrTooWide is returned when a diagram doesn't fit the available width.
+	ErrTooWide = errors.New("diagram: diagram too wide")
+)
+
+// Render renders the Mermaid diagram src as lines of box-drawing art no
+// wider than width.
+func Render(src string, width int) ([]string, error) {
+	lines := statements(src)
+	if len(lines) == 0 {
+		return nil, ErrUnsupported
+	}
+
+	header := strings.Fields(lines[0])
+	switch header[0] {
+	case "graph", "flowchart":
+		dir := "TD"
+		if len(header) > 1 {
+			dir = strings.ToUpper(header[1])
+		}
+		f, err := parseFlowchart(lines[1:])
+		if err != nil {
+			return nil, err
+		}
+		return f.render(dir, width)
+	case "sequenceDiagram":
+		s, err := parseSequence(lines[1:])
+		if err != nil {
+			return nil, err
+		}
+		return fit(s.render(), width)
+	}
+	return nil, ErrUnsupported
+}
+
+// statements splits a diagram into trimmed statements, dropping blank lines
+// and %% comments.
+func statements(src string) []string {
+	var out []string
+	for _, line := range strings.Split(src, "\n") {
+		for _, s := range strings.Split(line, ";") {
+			s = strings.TrimSpace(s)
+			if s == "" || strings.HasPrefix(s, "%%") {
+				continue
+			}
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func fit(lines []string, width int) ([]string, error) {
+	for _, l := range lines {
+		if ansi.StringWidth(l) > width {
+			return nil, ErrTooWide
+		}
+	}
+	return lines, nil
+}
+
+// Directions of line segments meeting in a canvas cell.
+const (
+	up = 1 << iota
+	down
+	left
+	right
+)
+
+var junctions = map[int]rune{
+	up:                       '│',
+	down:                     '│',
+	up | down:                '│',
+	left:                     '─',
+	right:                    '─',
+	left | right:             '─',
+	down | right:             '┌',
+	down | left:              '┐',
+	up | right:               '└',
+	up | left:                '┘',
+	up | down | right:        '├',
+	up | down | left:         '┤',
+	down | left | right:      '┬',
+	up | left | right:        '┴',
+	up | down | left | right: '┼',
+}
+
+// lineStyle selects the glyphs of straight line segments.
+type lineStyle int
+
+const (
+	solid lineStyle = iota
+	dotted
+	thick
+)
+
+var straights = map[lineStyle][2]rune{
+	solid:  {'│', '─'},
+	dotted: {'┆', '┄'},
+	thick:  {'┃', '━'},
+}
+
+// canvas is a character grid that lines, boxes and text are drawn onto.
+// Lines are tracked per cell so that crossing and touching lines join into
+// the right box-drawing characters.
+type canvas struct {
+	cells [][]rune
+	lines [][]int
+	style [][]lineStyle
+}
+
+func newCanvas(w, h int) *canvas {
+	c := &canvas{
+		cells: make([][]rune, h),
+		lines: make([][]int, h),
+		style: make([][]lineStyle, h),
+	}
+	for y := range c.cells {
+		c.cells[y] = []rune(strings.Repeat(" ", w))
+		c.lines[y] = make([]int, w)
+		c.style[y] = make([]lineStyle, w)
+	}
+	return c
+}
+
+func (c *canvas) in(x, y int) bool {
+	return y >= 0 && y < len(c.cells) && x >= 0 && x < len(c.cells[y])
+}
+
+func (c *canvas) set(x, y int, r rune) {
+	if c.in(x, y) {
+		c.cells[y][x] = r
+		c.lines[y][x] = 0
+	}
+}
+
+func (c *canvas) text(x, y int, s string) {
+	for _, r := range s {
+		c.set(x, y, r)
+		x++
+	}
+}
+
+func (c *canvas) join(x, y, dirs int, st lineStyle) {
+	if !c.in(x, y) {
+		return
+	}
+	c.lines[y][x] |= dirs
+	if st != solid {
+		c.style[y][x] = st
+	}
+	mask := c.lines[y][x]
+	r := junctions[mask]
+	switch mask {
+	case up, down, up | down:
+		r = straights[c.style[y][x]][0]
+	case left, right, left | right:
+		r = straights[c.style[y][x]][1]
+	}
+	c.cells[y][x] = r
+}
+
+// hline draws a horizontal line between x1 and x2 (inclusive) on row y.
+func (c *canvas) hline(x1, x2, y int, st lineStyle) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	for x := x1; x < x2; x++ {
+		c.join(x, y, right, st)
+		c.join(x+1, y, left, st)
+	}
+}
+
+// vline draws a vertical line between y1 and y2 (inclusive) on column x.
+func (c *canvas) vline(x, y1, y2 int, st lineStyle) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	for y := y1; y < y2; y++ {
+		c.join(x, y, down, st)
+		c.join(x, y+1, up, st)
+	}
+}
+
+// path draws an orthogonal line through the given points, joining the
+// segments at their corners.
+func (c *canvas) path(st lineStyle, points ...[2]int) {
+	for i := 1; i < len(points); i++ {
+		p, q := points[i-1], points[i]
+		if p[1] == q[1] {
+			c.hline(p[0], q[0], p[1], st)
+		} else {
+			c.vline(p[0], p[1], q[1], st)
+		}
+	}
+}
+
+// box corners by shape.
+var corners = map[string][4]rune{
+	"rect":     {'┌', '┐', '└', '┘'},
+	"round":    {'╭', '╮', '╰', '╯'},
+	"decision": {'╱', '╲', '╲', '╱'},
+}
+
+// box draws a box of the given shape with its text centered on the middle
+// row.
+func (c *canvas) box(x, y, w, h int, shape, text string) {
+	k, ok := corners[shape]
+	if !ok {
+		k = corners["rect"]
+	}
+	for i := x + 1; i < x+w-1; i++ {
+		c.set(i, y, '─')
+		c.set(i, y+h-1, '─')
+	}
+	for j := y + 1; j < y+h-1; j++ {
+		c.set(x, j, '│')
+		c.set(x+w-1, j, '│')
+		for i := x + 1; i < x+w-1; i++ {
+			c.set(i, j, ' ')
+		}
+	}
+	c.set(x, y, k[0])
+	c.set(x+w-1, y, k[1])
+	c.set(x, y+h-1, k[2])
+	c.set(x+w-1, y+h-1, k[3])
+	c.text(x+(w-ansi.StringWidth(text))/2, y+h/2, text) //nolint: mnd
+}
+
+func (c *canvas) String() []string {
+	lines := make([]string, 0, len(c.cells))
+	for _, row := range c.cells {
+		lines = append(lines, strings.TrimRight(string(row), " "))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
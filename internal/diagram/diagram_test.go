@@ -0,0 +1,99 @@
+package diagram_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/diagram"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		width    int
+		expected []string
+		err      error
+	}{
+		{
+			name:  "flowchart top-down",
+			src:   "graph TD\n  A[One] --> B(Two)",
+			width: 40,
+			expected: []string{
+				"┌─────┐",
+				"│ One │",
+				"└─────┘",
+				"   │",
+				"   │",
+				"   ▼",
+				"╭─────╮",
+				"│ Two │",
+				"╰─────╯",
+			},
+		},
+		{
+			name:  "flowchart left-right",
+			src:   "flowchart LR\n  A[One] --> B[Two]",
+			width: 40,
+			expected: []string{
+				"┌─────┐      ┌─────┐",
+				"│ One │─────▶│ Two │",
+				"└─────┘      └─────┘",
+			},
+		},
+		{
+			name:  "sequence diagram",
+			src:   "sequenceDiagram\n  A->>B: hi",
+			width: 40,
+			expected: []string{
+				"┌───┐  ┌───┐",
+				"│ A │  │ B │",
+				"└─┬─┘  └─┬─┘",
+				"  ┆  hi  ┆",
+				"  ┆─────▶┆",
+				"┌─┴─┐  ┌─┴─┐",
+				"│ A │  │ B │",
+				"└───┘  └───┘",
+			},
+		},
+		{
+			name:  "too wide",
+			src:   "graph LR\n  A[A rather long label] --> B[Another long label]",
+			width: 10,
+			err:   diagram.ErrTooWide,
+		},
+		{
+			name:  "flowchart without nodes",
+			src:   "graph TD",
+			width: 40,
+			err:   diagram.ErrUnsupported,
+		},
+		{
+			name:  "flowchart with only a comment",
+			src:   "flowchart LR\n  %% nothing here yet",
+			width: 40,
+			err:   diagram.ErrUnsupported,
+		},
+		{
+			name:  "unsupported",
+			src:   "pie\n  \"Dogs\" : 386",
+			width: 40,
+			err:   diagram.ErrUnsupported,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lines, err := diagram.Render(tc.src, tc.width)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			got := strings.Join(lines, "\n")
+			want := strings.Join(tc.expected, "\n")
+			if got != want {
+				t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,584 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+type flowNode struct {
+	id    string
+	label string
+	shape string
+}
+
+type flowEdge struct {
+	from, to int
+	label    string
+	style    lineStyle
+	arrow    bool
+}
+
+// flowchart is a parsed Mermaid graph/flowchart.
+type flowchart struct {
+	nodes []*flowNode
+	index map[string]int
+	edges []flowEdge
+}
+
+// skipped statements don't affect the rendered diagram.
+var skipped = []string{"subgraph", "end", "classDef", "class ", "style ", "click ", "linkStyle", "direction"}
+
+func parseFlowchart(lines []string) (*flowchart, error) {
+	f := &flowchart{index: map[string]int{}}
+	for _, line := range lines {
+		skip := false
+		for _, s := range skipped {
+			if line == s || strings.HasPrefix(line, s+" ") || strings.HasPrefix(line, s) && strings.HasSuffix(s, " ") {
+				skip = true
+			}
+		}
+		if skip {
+			continue
+		}
+		if err := f.statement(line); err != nil {
+			return nil, err
+		}
+	}
+	if len(f.nodes) == 0 {
+		return nil, ErrUnsupported
+	}
+	return f, nil
+}
+
+// statement parses a chain of nodes and links, like "A --> B & C -- no --> D".
+func (f *flowchart) statement(s string) error {
+	from, rest, err := f.nodeList(s)
+	if err != nil {
+		return err
+	}
+	for rest != "" {
+		l, r, ok := parseLink(rest)
+		if !ok {
+			return fmt.Errorf("diagram: invalid link in %q", s)
+		}
+		to, r, err := f.nodeList(r)
+		if err != nil {
+			return err
+		}
+		for _, a := range from {
+			for _, b := range to {
+				e := l
+				e.from, e.to = a, b
+				f.edges = append(f.edges, e)
+			}
+		}
+		from, rest = to, r
+	}
+	return nil
+}
+
+func (f *flowchart) nodeList(s string) ([]int, string, error) {
+	var ids []int
+	for {
+		id, rest, err := f.node(s)
+		if err != nil {
+			return nil, "", err
+		}
+		ids = append(ids, id)
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, "&") {
+			return ids, rest, nil
+		}
+		s = rest[1:]
+	}
+}
+
+var nodeID = regexp.MustCompile(`^[\p{L}\p{N}_]+`)
+
+// shapes maps node shape delimiters to the shape they are drawn with.
+var shapes = []struct {
+	open, close, shape string
+}{
+	{"(((", ")))", "round"},
+	{"((", "))", "round"},
+	{"([", "])", "round"},
+	{"[[", "]]", "rect"},
+	{"[(", ")]", "rect"},
+	{"{{", "}}", "decision"},
+	{"[/", "/]", "rect"},
+	{`[\`, `\]`, "rect"},
+	{"[", "]", "rect"},
+	{"(", ")", "round"},
+	{"{", "}", "decision"},
+	{">", "]", "rect"},
+}
+
+func (f *flowchart) node(s string) (int, string, error) {
+	s = strings.TrimSpace(s)
+	id := nodeID.FindString(s)
+	if id == "" {
+		return 0, "", fmt.Errorf("diagram: expected node in %q", s)
+	}
+	s = s[len(id):]
+
+	label, shape := "", ""
+	for _, sh := range shapes {
+		if !strings.HasPrefix(s, sh.open) {
+			continue
+		}
+		end := strings.Index(s[len(sh.open):], sh.close)
+		if end < 0 {
+			return 0, "", fmt.Errorf("diagram: unterminated node %q", id)
+		}
+		label = strings.TrimSpace(s[len(sh.open) : len(sh.open)+end])
+		label = strings.Trim(label, `"`)
+		shape = sh.shape
+		s = s[len(sh.open)+end+len(sh.close):]
+		break
+	}
+
+	i, ok := f.index[id]
+	if !ok {
+		i = len(f.nodes)
+		f.index[id] = i
+		f.nodes = append(f.nodes, &flowNode{id: id, label: id, shape: "rect"})
+	}
+	if shape != "" {
+		f.nodes[i].label = label
+		f.nodes[i].shape = shape
+	}
+	return i, s, nil
+}
+
+var (
+	textLink  = regexp.MustCompile(`^(--|==|-\.)\s+(.+?)\s+(-{2,}>|={2,}>|\.-+>|-{3,}|={3,}|\.-+)`)
+	plainLink = regexp.MustCompile(`^<?(-{2,}>|-{3,}|={2,}>|={3,}|-\.+->|-\.+-|--[ox]|==[ox])(\|([^|]*)\|)?`)
+)
+
+func parseLink(s string) (flowEdge, string, bool) {
+	s = strings.TrimSpace(s)
+	var e flowEdge
+	var op string
+	if m := textLink.FindStringSubmatch(s); m != nil {
+		op = m[1] + m[3]
+		e.label = m[2]
+		s = s[len(m[0]):]
+	} else if m := plainLink.FindStringSubmatch(s); m != nil {
+		op = m[1]
+		e.label = strings.TrimSpace(m[3])
+		s = s[len(m[0]):]
+	} else {
+		return e, s, false
+	}
+
+	switch {
+	case strings.Contains(op, "="):
+		e.style = thick
+	case strings.Contains(op, "."):
+		e.style = dotted
+	}
+	last := op[len(op)-1]
+	e.arrow = last == '>' || last == 'x' || last == 'o'
+	return e, s, true
+}
+
+// vnode is a node placed in a layer. Dummy nodes (node < 0) route edges
+// that span multiple layers.
+type vnode struct {
+	node  int
+	layer int
+	order int
+	x, y  int
+	w, h  int
+}
+
+// vedge is an edge between nodes of adjacent layers.
+type vedge struct {
+	from, to  int
+	label     string
+	style     lineStyle
+	headEnd   bool
+	headStart bool
+}
+
+type graphLayout struct {
+	nodes  []*vnode
+	edges  []vedge
+	layers [][]int
+}
+
+// layout assigns the flowchart's nodes to layers, longest path first,
+// breaking cycles and inserting dummy nodes for long edges. If reverse is
+// set, edges point against the layer order.
+func (f *flowchart) layout(reverse bool) *graphLayout {
+	n := len(f.nodes)
+	type edge struct {
+		from, to  int
+		src       flowEdge
+		headEnd   bool
+		headStart bool
+	}
+	var edges []edge
+	for _, e := range f.edges {
+		if e.from == e.to {
+			continue
+		}
+		ed := edge{from: e.from, to: e.to, src: e, headEnd: e.arrow}
+		if reverse {
+			ed.from, ed.to = ed.to, ed.from
+			ed.headEnd, ed.headStart = false, e.arrow
+		}
+		edges = append(edges, ed)
+	}
+
+	// break cycles by reversing back edges found in a depth-first search
+	state := make([]int, n)
+	adj := make([][]int, n)
+	for i, e := range edges {
+		adj[e.from] = append(adj[e.from], i)
+	}
+	var visit func(v int)
+	visit = func(v int) {
+		state[v] = 1
+		for _, i := range adj[v] {
+			e := &edges[i]
+			switch state[e.to] {
+			case 0:
+				visit(e.to)
+			case 1:
+				e.from, e.to = e.to, e.from
+				e.headEnd, e.headStart = e.headStart, e.headEnd
+			}
+		}
+		state[v] = 2 //nolint: mnd
+	}
+	for v := 0; v < n; v++ {
+		if state[v] == 0 {
+			visit(v)
+		}
+	}
+
+	// longest path layering
+	layer := make([]int, n)
+	for changed := true; changed; {
+		changed = false
+		for _, e := range edges {
+			if layer[e.to] < layer[e.from]+1 {
+				layer[e.to] = layer[e.from] + 1
+				changed = true
+			}
+		}
+	}
+
+	g := &graphLayout{}
+	add := func(node, l int) int {
+		for len(g.layers) <= l {
+			g.layers = append(g.layers, nil)
+		}
+		g.nodes = append(g.nodes, &vnode{node: node, layer: l, order: len(g.layers[l])})
+		g.layers[l] = append(g.layers[l], len(g.nodes)-1)
+		return len(g.nodes) - 1
+	}
+	for v := 0; v < n; v++ {
+		add(v, layer[v])
+	}
+	for _, e := range edges {
+		from := e.from
+		for l := layer[e.from] + 1; l < layer[e.to]; l++ {
+			d := add(-1, l)
+			g.edges = append(g.edges, vedge{from: from, to: d, style: e.src.style, headStart: e.headStart && from == e.from})
+			from = d
+		}
+		g.edges = append(g.edges, vedge{
+			from:      from,
+			to:        e.to,
+			label:     e.src.label,
+			style:     e.src.style,
+			headEnd:   e.headEnd,
+			headStart: e.headStart && from == e.from,
+		})
+	}
+
+	g.order()
+	return g
+}
+
+// order reduces edge crossings by sorting each layer by the average
+// position of its predecessors.
+func (g *graphLayout) order() {
+	preds := make([][]int, len(g.nodes))
+	for _, e := range g.edges {
+		preds[e.to] = append(preds[e.to], e.from)
+	}
+	for pass := 0; pass < 2; pass++ {
+		for l := 1; l < len(g.layers); l++ {
+			bary := map[int]float64{}
+			for _, v := range g.layers[l] {
+				bary[v] = float64(g.nodes[v].order)
+				if len(preds[v]) > 0 {
+					sum := 0.0
+					for _, p := range preds[v] {
+						sum += float64(g.nodes[p].order)
+					}
+					bary[v] = sum / float64(len(preds[v]))
+				}
+			}
+			sort.SliceStable(g.layers[l], func(i, j int) bool {
+				return bary[g.layers[l][i]] < bary[g.layers[l][j]]
+			})
+			for i, v := range g.layers[l] {
+				g.nodes[v].order = i
+			}
+		}
+	}
+}
+
+func (f *flowchart) render(dir string, width int) ([]string, error) {
+	if dir == "LR" || dir == "RL" {
+		lines, err := fit(f.renderLR(f.layout(dir == "RL")), width)
+		if err == nil {
+			return lines, nil
+		}
+		// try to fit the diagram by laying it out vertically
+	}
+	return fit(f.renderTD(f.layout(dir == "BT")), width)
+}
+
+const (
+	boxHeight = 3
+	layerGap  = 3
+	nodeGap   = 3
+)
+
+func (f *flowchart) label(v *vnode) string {
+	if v.node < 0 {
+		return ""
+	}
+	return f.nodes[v.node].label
+}
+
+func (f *flowchart) renderTD(g *graphLayout) []string {
+	// room for edge labels, which are drawn right of the arrow
+	labels := make([]int, len(g.nodes))
+	for _, e := range g.edges {
+		labels[e.to] = max(labels[e.to], ansi.StringWidth(e.label))
+	}
+
+	width := 0
+	rows := make([]int, len(g.layers))
+	for l, layer := range g.layers {
+		x := 0
+		for i, id := range layer {
+			v := g.nodes[id]
+			v.w, v.h = 1, boxHeight
+			if v.node >= 0 {
+				v.w = ansi.StringWidth(f.label(v)) + 4 //nolint: mnd
+			}
+			if i > 0 {
+				prev := g.nodes[layer[i-1]]
+				x += nodeGap + max(labels[layer[i-1]]-(prev.w-prev.w/2), 0) //nolint: mnd
+			}
+			v.x, v.y = x, l*(boxHeight+layerGap)
+			x += v.w
+		}
+		rows[l] = x
+		width = max(width, x)
+	}
+	for l, layer := range g.layers {
+		for _, id := range layer {
+			g.nodes[id].x += (width - rows[l]) / 2 //nolint: mnd
+		}
+	}
+	g.align(labels)
+	width = 0
+	for _, v := range g.nodes {
+		width = max(width, v.x+v.w)
+	}
+	// the right-most label may extend past the last node
+	for i, v := range g.nodes {
+		if labels[i] > 0 {
+			width = max(width, v.x+v.w/2+2+labels[i]) //nolint: mnd
+		}
+	}
+
+	height := len(g.layers)*(boxHeight+layerGap) - layerGap
+	c := newCanvas(width, height)
+	for _, e := range g.edges {
+		u, v := g.nodes[e.from], g.nodes[e.to]
+		sx, tx := u.x+u.w/2, v.x+v.w/2 //nolint: mnd
+		exit, entry := u.y+u.h, v.y-1
+		mid := exit + 1
+		c.path(e.style, [2]int{sx, exit}, [2]int{sx, mid}, [2]int{tx, mid}, [2]int{tx, entry})
+	}
+	for _, v := range g.nodes {
+		if v.node < 0 {
+			c.vline(v.x, v.y-1, v.y+v.h, solid)
+		}
+	}
+	for _, e := range g.edges {
+		u, v := g.nodes[e.from], g.nodes[e.to]
+		sx, tx := u.x+u.w/2, v.x+v.w/2 //nolint: mnd
+		if e.headEnd && v.node >= 0 {
+			c.set(tx, v.y-1, '▼')
+		}
+		if e.headStart && u.node >= 0 {
+			c.set(sx, u.y+u.h, '▲')
+		}
+	}
+	for _, v := range g.nodes {
+		if v.node >= 0 {
+			c.box(v.x, v.y, v.w, v.h, f.nodes[v.node].shape, f.label(v))
+		}
+	}
+	for _, e := range g.edges {
+		if e.label != "" {
+			v := g.nodes[e.to]
+			c.text(v.x+v.w/2+2, v.y-1, e.label) //nolint: mnd
+		}
+	}
+	return c.String()
+}
+
+// align moves nodes below the center of their predecessors, then moves
+// nodes without predecessors above the center of their successors, keeping
+// nodes of a layer from overlapping.
+func (g *graphLayout) align(labels []int) {
+	preds := make([][]int, len(g.nodes))
+	succs := make([][]int, len(g.nodes))
+	for _, e := range g.edges {
+		preds[e.to] = append(preds[e.to], e.from)
+		succs[e.from] = append(succs[e.from], e.to)
+	}
+
+	place := func(layer []int, neighbors [][]int) {
+		right := 0
+		for i, id := range layer {
+			v := g.nodes[id]
+			x := v.x
+			if len(neighbors[id]) > 0 {
+				sum := 0
+				for _, n := range neighbors[id] {
+					sum += g.nodes[n].x + g.nodes[n].w/2 //nolint: mnd
+				}
+				x = sum/len(neighbors[id]) - v.w/2 //nolint: mnd
+			}
+			if i > 0 {
+				prev := g.nodes[layer[i-1]]
+				x = max(x, right+nodeGap+max(labels[layer[i-1]]-(prev.w-prev.w/2), 0)) //nolint: mnd
+			}
+			v.x = max(x, 0)
+			right = v.x + v.w
+		}
+	}
+	for l := 1; l < len(g.layers); l++ {
+		place(g.layers[l], preds)
+	}
+	for l := len(g.layers) - 2; l >= 0; l-- {
+		roots := make([][]int, len(g.nodes))
+		for _, id := range g.layers[l] {
+			if len(preds[id]) == 0 {
+				roots[id] = succs[id]
+			}
+		}
+		place(g.layers[l], roots)
+	}
+}
+
+func (f *flowchart) renderLR(g *graphLayout) []string {
+	// room for edge labels, which are drawn on the edge's last segment
+	gaps := make([]int, len(g.layers))
+	for i := range gaps {
+		gaps[i] = 6 //nolint: mnd
+	}
+	for _, e := range g.edges {
+		l := g.nodes[e.from].layer
+		gaps[l] = max(gaps[l], ansi.StringWidth(e.label)+6) //nolint: mnd
+	}
+
+	cols := make([]int, len(g.layers))
+	for l, layer := range g.layers {
+		cols[l] = 1
+		for _, id := range layer {
+			if v := g.nodes[id]; v.node >= 0 {
+				cols[l] = max(cols[l], ansi.StringWidth(f.label(v))+4) //nolint: mnd
+			}
+		}
+	}
+
+	height, width := 0, 0
+	heights := make([]int, len(g.layers))
+	for l, layer := range g.layers {
+		y := 0
+		for i, id := range layer {
+			v := g.nodes[id]
+			v.w, v.h = cols[l], 1
+			if v.node >= 0 {
+				v.w, v.h = ansi.StringWidth(f.label(v))+4, boxHeight //nolint: mnd
+			}
+			if i > 0 {
+				y++
+			}
+			v.x, v.y = width+(cols[l]-v.w)/2, y //nolint: mnd
+			y += v.h
+		}
+		heights[l] = y
+		height = max(height, y)
+		width += cols[l]
+		if l < len(g.layers)-1 {
+			width += gaps[l]
+		}
+	}
+	for l, layer := range g.layers {
+		for _, id := range layer {
+			g.nodes[id].y += (height - heights[l]) / 2 //nolint: mnd
+		}
+	}
+
+	colEnd := func(l int) int {
+		end := 0
+		for i := 0; i <= l; i++ {
+			end += cols[i]
+			if i < l {
+				end += gaps[i]
+			}
+		}
+		return end
+	}
+
+	c := newCanvas(width, height)
+	for _, e := range g.edges {
+		u, v := g.nodes[e.from], g.nodes[e.to]
+		sy, ty := u.y+u.h/2, v.y+v.h/2 //nolint: mnd
+		mx := colEnd(u.layer) + 1
+		c.path(e.style, [2]int{u.x + u.w, sy}, [2]int{mx, sy}, [2]int{mx, ty}, [2]int{v.x - 1, ty})
+	}
+	for _, v := range g.nodes {
+		if v.node < 0 {
+			c.hline(v.x-1, v.x+v.w, v.y, solid)
+		}
+	}
+	for _, e := range g.edges {
+		u, v := g.nodes[e.from], g.nodes[e.to]
+		if e.headEnd && v.node >= 0 {
+			c.set(v.x-1, v.y+v.h/2, '▶') //nolint: mnd
+		}
+		if e.headStart && u.node >= 0 {
+			c.set(u.x+u.w, u.y+u.h/2, '◀') //nolint: mnd
+		}
+		if e.label != "" {
+			start, end := colEnd(u.layer)+2, v.x-2 //nolint: mnd
+			lw := ansi.StringWidth(e.label)
+			c.text(start+(end-start+1-lw)/2, v.y+v.h/2, e.label) //nolint: mnd
+		}
+	}
+	for _, v := range g.nodes {
+		if v.node >= 0 {
+			c.box(v.x, v.y, v.w, v.h, f.nodes[v.node].shape, f.label(v))
+		}
+	}
+	return c.String()
+}
@@ -0,0 +1,238 @@
+package diagram
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+type message struct {
+	from, to int
+	text     string
+	style    lineStyle
+	head     rune
+}
+
+type note struct {
+	from, to int
+	text     string
+	side     string
+}
+
+// event is a message or a note, in diagram order.
+type event struct {
+	msg  *message
+	note *note
+}
+
+// sequence is a parsed Mermaid sequence diagram.
+type sequence struct {
+	participants []string
+	index        map[string]int
+	events       []event
+}
+
+var (
+	participantRe = regexp.MustCompile(`^(participant|actor)\s+(\S+)(\s+as\s+(.+))?$`)
+	messageRe     = regexp.MustCompile(`^([^-]+?)\s*(-->>|->>|-->|->|--x|-x|--\)|-\))\s*([+-]?)([^:]+?)\s*(:\s*(.*))?$`)
+	noteRe        = regexp.MustCompile(`(?i)^note\s+(left of|right of|over)\s+([^:]+?)\s*:\s*(.*)$`)
+)
+
+func parseSequence(lines []string) (*sequence, error) {
+	s := &sequence{index: map[string]int{}}
+	for _, line := range lines {
+		if m := participantRe.FindStringSubmatch(line); m != nil {
+			name := m[2]
+			if m[4] != "" {
+				name = m[4]
+			}
+			s.participant(m[2], name)
+			continue
+		}
+		if m := noteRe.FindStringSubmatch(line); m != nil {
+			ids := strings.Split(m[2], ",")
+			n := &note{
+				from: s.participant(strings.TrimSpace(ids[0]), ""),
+				side: strings.ToLower(m[1]),
+				text: m[3],
+			}
+			n.to = n.from
+			if len(ids) > 1 {
+				n.to = s.participant(strings.TrimSpace(ids[1]), "")
+			}
+			s.events = append(s.events, event{note: n})
+			continue
+		}
+		if m := messageRe.FindStringSubmatch(line); m != nil {
+			msg := &message{
+				from: s.participant(strings.TrimSpace(m[1]), ""),
+				to:   s.participant(strings.TrimSpace(m[4]), ""),
+				text: m[6],
+				head: '▶',
+			}
+			if strings.HasPrefix(m[2], "--") {
+				msg.style = dotted
+			}
+			switch strings.TrimLeft(m[2], "-") {
+			case ">":
+				msg.head = 0
+			case "x":
+				msg.head = '×'
+			}
+			s.events = append(s.events, event{msg: msg})
+		}
+		// blocks like loop, alt and activations are not drawn
+	}
+	if len(s.participants) == 0 {
+		return nil, ErrUnsupported
+	}
+	return s, nil
+}
+
+// participant returns the index of the participant with the given id,
+// adding it if necessary.
+func (s *sequence) participant(id, name string) int {
+	if i, ok := s.index[id]; ok {
+		if name != "" {
+			s.participants[i] = name
+		}
+		return i
+	}
+	if name == "" {
+		name = id
+	}
+	s.index[id] = len(s.participants)
+	s.participants = append(s.participants, name)
+	return len(s.participants) - 1
+}
+
+func (s *sequence) render() []string {
+	n := len(s.participants)
+	widths := make([]int, n)
+	for i, p := range s.participants {
+		widths[i] = ansi.StringWidth(p) + 4 //nolint: mnd
+	}
+
+	// position lifelines so that boxes and message texts fit between them
+	xs := make([]int, n)
+	xs[0] = widths[0] / 2 //nolint: mnd
+	for i := 1; i < n; i++ {
+		xs[i] = xs[i-1] + (widths[i-1]+1)/2 + widths[i]/2 + 2 //nolint: mnd
+	}
+	require := func(a, b, d int) {
+		if a > b {
+			a, b = b, a
+		}
+		if short := d - (xs[b] - xs[a]); short > 0 {
+			for i := b; i < n; i++ {
+				xs[i] += short
+			}
+		}
+	}
+	width := xs[n-1] + (widths[n-1]+1)/2 //nolint: mnd
+	for _, e := range s.events {
+		switch {
+		case e.msg != nil && e.msg.from != e.msg.to:
+			require(e.msg.from, e.msg.to, ansi.StringWidth(e.msg.text)+4) //nolint: mnd
+		case e.msg != nil:
+			width = max(width, xs[e.msg.from]+ansi.StringWidth(e.msg.text)+5) //nolint: mnd
+		case e.note.side == "right of" && e.note.from < n-1:
+			require(e.note.from, e.note.from+1, ansi.StringWidth(e.note.text)+6) //nolint: mnd
+		case e.note.side == "left of" && e.note.from > 0:
+			require(e.note.from-1, e.note.from, ansi.StringWidth(e.note.text)+6) //nolint: mnd
+		}
+	}
+	width = max(width, xs[n-1]+(widths[n-1]+1)/2) //nolint: mnd
+
+	// make room for notes beyond the outermost lifelines
+	shift := 0
+	for _, e := range s.events {
+		if e.note != nil {
+			x, w := s.notePosition(e.note, xs)
+			shift = max(shift, -x)
+			width = max(width, x+w)
+		}
+	}
+	for i := range xs {
+		xs[i] += shift
+	}
+	width += shift
+
+	height := 2 * boxHeight //nolint: mnd
+	for _, e := range s.events {
+		if e.note != nil {
+			height += boxHeight
+		} else {
+			height += 2 //nolint: mnd
+		}
+	}
+	height++
+
+	c := newCanvas(width, height)
+	for _, x := range xs {
+		c.vline(x, boxHeight-1, height-boxHeight, dotted)
+	}
+
+	y := boxHeight
+	for _, e := range s.events {
+		if e.note != nil {
+			x, w := s.notePosition(e.note, xs)
+			c.box(x, y, w, boxHeight, "rect", e.note.text)
+			y += boxHeight
+			continue
+		}
+
+		m := e.msg
+		from, to := xs[m.from], xs[m.to]
+		if m.from == m.to {
+			c.path(m.style, [2]int{from + 1, y}, [2]int{from + 3, y}, [2]int{from + 3, y + 1}, [2]int{from + 1, y + 1})
+			c.text(from+5, y, m.text) //nolint: mnd
+			if m.head == '▶' {
+				c.set(from+1, y+1, '◀')
+			} else if m.head != 0 {
+				c.set(from+1, y+1, m.head)
+			}
+			y += 2 //nolint: mnd
+			continue
+		}
+
+		c.text((from+to-ansi.StringWidth(m.text))/2+1, y, m.text) //nolint: mnd
+		dir := 1
+		if to < from {
+			dir = -1
+		}
+		c.hline(from+dir, to-dir, y+1, m.style)
+		if m.head == '▶' && dir < 0 {
+			c.set(to-dir, y+1, '◀')
+		} else if m.head != 0 {
+			c.set(to-dir, y+1, m.head)
+		}
+		y += 2 //nolint: mnd
+	}
+
+	for i, p := range s.participants {
+		x := xs[i] - widths[i]/2 //nolint: mnd
+		c.box(x, 0, widths[i], boxHeight, "rect", p)
+		c.box(x, y, widths[i], boxHeight, "rect", p)
+		c.set(xs[i], boxHeight-1, '┬')
+		c.set(xs[i], y, '┴')
+	}
+	return c.String()
+}
+
+// notePosition returns the left edge and width of a note's box.
+func (s *sequence) notePosition(n *note, xs []int) (int, int) {
+	w := ansi.StringWidth(n.text) + 4 //nolint: mnd
+	switch n.side {
+	case "right of":
+		return xs[n.from] + 2, w //nolint: mnd
+	case "left of":
+		return xs[n.from] - 1 - w, w
+	}
+	a, b := min(xs[n.from], xs[n.to]), max(xs[n.from], xs[n.to])
+	if b-a+5 > w { //nolint: mnd
+		w = b - a + 5 //nolint: mnd
+	}
+	return (a+b)/2 - w/2, w //nolint: mnd
+}
@@ -0,0 +1,203 @@
+// Package fence parses the info string of fenced code blocks.
+package fence
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A Range is an inclusive range of line numbers.
+type Range struct {
+	From, To int
+}
+
+// Info holds the attributes of a fenced code block's info string, e.g.
+//
+//	go title="main.go" {3-5} linenos
+type Info struct {
+	Language string
+	Title    string
+
+	// LineNumbers enables line numbers, counting from Start.
+	LineNumbers bool
+	Start       int
+
+	// Highlight holds the ranges of lines to highlight, using the same
+	// numbering as the line numbers.
+	Highlight []Range
+
+	// Attributes holds all key=value attributes of the info string.
+	Attributes map[string]string
+}
+
+// Highlighted returns whether the given line is highlighted.
+func (i Info) Highlighted(line int) bool {
+	for _, r := range i.Highlight {
+		if line >= r.From && line <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse parses a fenced code block's info string. The first word is the
+// language, followed by flags (linenos), key=value pairs (title="main.go",
+// hl_lines="3 5-7", linenostart=10) and line ranges in braces ({3-5,7}).
+// Pandoc-style attribute braces ({.go .numberLines startFrom=10}) are
+// supported as well.
+func Parse(info string) Info {
+	i := Info{
+		Start:      1,
+		Attributes: map[string]string{},
+	}
+
+	tokens := tokenize(info, false)
+	if len(tokens) > 0 && !strings.ContainsAny(tokens[0], "={") {
+		i.Language = tokens[0]
+		tokens = tokens[1:]
+	}
+	for _, t := range tokens {
+		i.parseToken(t)
+	}
+	return i
+}
+
+func (i *Info) parseToken(t string) {
+	switch {
+	case strings.HasPrefix(t, "{"):
+		inner := strings.TrimSuffix(strings.TrimPrefix(t, "{"), "}")
+		inner = strings.TrimSpace(inner)
+		if strings.ContainsAny(inner, "=.#") || strings.IndexFunc(inner, unicode.IsLetter) >= 0 {
+			for _, t := range tokenize(inner, true) {
+				i.parseToken(t)
+			}
+			return
+		}
+		i.Highlight = append(i.Highlight, parseRanges(inner)...)
+
+	case strings.HasPrefix(t, "."):
+		class := t[1:]
+		if isLineNumbersFlag(class) {
+			i.LineNumbers = true
+		} else if i.Language == "" {
+			i.Language = class
+		}
+
+	case strings.HasPrefix(t, "#"):
+		// element IDs have no meaning in a terminal
+
+	case strings.Contains(t, "="):
+		k, v, _ := strings.Cut(t, "=")
+		v = unquote(v)
+		i.Attributes[k] = v
+		i.parseAttribute(strings.ToLower(k), v)
+
+	case isLineNumbersFlag(t):
+		i.LineNumbers = true
+	}
+}
+
+func (i *Info) parseAttribute(k, v string) {
+	switch k {
+	case "title", "filename", "file":
+		i.Title = v
+	case "hl_lines", "highlight", "hl":
+		i.Highlight = append(i.Highlight, parseRanges(v)...)
+	case "linenostart", "startfrom", "start-from", "start":
+		if n, err := strconv.Atoi(v); err == nil {
+			i.Start = n
+		}
+	case "linenos", "linenumbers", "line-numbers", "shownumbers":
+		i.LineNumbers = v != "false" && v != "0" && v != ""
+	}
+}
+
+func isLineNumbersFlag(s string) bool {
+	switch strings.ToLower(s) {
+	case "linenos", "linenumbers", "line-numbers", "numberlines", "number-lines", "showlinenumbers":
+		return true
+	}
+	return false
+}
+
+// parseRanges parses a list of line ranges separated by commas or spaces,
+// e.g. "1,3-5 7".
+func parseRanges(s string) []Range {
+	var ranges []Range
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		from, to, isRange := strings.Cut(f, "-")
+		a, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		b := a
+		if isRange {
+			if b, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+		if b < a {
+			a, b = b, a
+		}
+		ranges = append(ranges, Range{From: a, To: b})
+	}
+	return ranges
+}
+
+// tokenize splits an info string on whitespace, and optionally commas,
+// keeping quoted values and braced groups together. A brace directly
+// following a word starts a new token, so "go{1,3}" yields "go" and "{1,3}".
+func tokenize(s string, commas bool) []string {
+	var tokens []string
+	var cur strings.Builder
+	var quote rune
+	depth := 0
+
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			cur.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			cur.WriteRune(r)
+		case r == '{':
+			if depth == 0 {
+				flush()
+			}
+			depth++
+			cur.WriteRune(r)
+		case r == '}' && depth > 0:
+			depth--
+			cur.WriteRune(r)
+			if depth == 0 {
+				flush()
+			}
+		case (unicode.IsSpace(r) || commas && r == ',') && depth == 0:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package fence_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/fence"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     string
+		expected fence.Info
+	}{
+		{
+			name:     "empty",
+			info:     "",
+			expected: fence.Info{Start: 1, Attributes: map[string]string{}},
+		},
+		{
+			name:     "language only",
+			info:     "go",
+			expected: fence.Info{Language: "go", Start: 1, Attributes: map[string]string{}},
+		},
+		{
+			name: "title, ranges and flag",
+			info: `go title="main.go" {3-5} linenos`,
+			expected: fence.Info{
+				Language:    "go",
+				Title:       "main.go",
+				LineNumbers: true,
+				Start:       1,
+				Highlight:   []fence.Range{{3, 5}},
+				Attributes:  map[string]string{"title": "main.go"},
+			},
+		},
+		{
+			name: "attached ranges",
+			info: "py{1, 4-6}",
+			expected: fence.Info{
+				Language:   "py",
+				Start:      1,
+				Highlight:  []fence.Range{{1, 1}, {4, 6}},
+				Attributes: map[string]string{},
+			},
+		},
+		{
+			name: "hugo style",
+			info: `sh {linenos=table,hl_lines="2 4-5",linenostart=10}`,
+			expected: fence.Info{
+				Language:    "sh",
+				LineNumbers: true,
+				Start:       10,
+				Highlight:   []fence.Range{{2, 2}, {4, 5}},
+				Attributes:  map[string]string{"linenos": "table", "hl_lines": "2 4-5", "linenostart": "10"},
+			},
+		},
+		{
+			name: "pandoc style",
+			info: `{.haskell .numberLines startFrom="100" #snippet}`,
+			expected: fence.Info{
+				Language:    "haskell",
+				LineNumbers: true,
+				Start:       100,
+				Attributes:  map[string]string{"startFrom": "100"},
+			},
+		},
+		{
+			name: "quoted title with spaces",
+			info: `rust filename='src/main file.rs' hl_lines=2-1`,
+			expected: fence.Info{
+				Language:   "rust",
+				Title:      "src/main file.rs",
+				Start:      1,
+				Highlight:  []fence.Range{{1, 2}},
+				Attributes: map[string]string{"filename": "src/main file.rs", "hl_lines": "2-1"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fence.Parse(tc.info)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestHighlighted(t *testing.T) {
+	i := fence.Parse("go {2,4-5}")
+	for line, want := range map[int]bool{1: false, 2: true, 3: false, 4: true, 5: true, 6: false} {
+		if got := i.Highlighted(line); got != want {
+			t.Errorf("line %d: expected %v, got %v", line, want, got)
+		}
+	}
+}
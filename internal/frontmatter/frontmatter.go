@@ -0,0 +1,161 @@
+// Package frontmatter provides a goldmark extension that detects YAML (---)
+// and TOML (+++) front matter at the start of a document, strips it from the
+// rendered output and stores its values as the document's metadata.
+package frontmatter
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Front matter formats.
+const (
+	YAML = "yaml"
+	TOML = "toml"
+)
+
+// KindFrontMatter is the NodeKind of a FrontMatter node.
+var KindFrontMatter = ast.NewNodeKind("FrontMatter")
+
+// FrontMatter is a block node holding a document's front matter.
+type FrontMatter struct {
+	ast.BaseBlock
+
+	// Format is either YAML or TOML.
+	Format string
+
+	// Values holds the parsed front matter.
+	Values map[string]interface{}
+}
+
+// Kind implements ast.Node.Kind.
+func (n *FrontMatter) Kind() ast.NodeKind {
+	return KindFrontMatter
+}
+
+// IsRaw implements ast.Node.IsRaw.
+func (n *FrontMatter) IsRaw() bool {
+	return true
+}
+
+// Dump implements ast.Node.Dump.
+func (n *FrontMatter) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Format": n.Format,
+	}, nil)
+}
+
+// Parse parses front matter of the given format.
+func Parse(format string, b []byte) (map[string]interface{}, error) {
+	if format == TOML {
+		return parseTOML(b)
+	}
+	return parseYAML(b)
+}
+
+type frontMatterParser struct{}
+
+func delimiter(line []byte) (string, []byte) {
+	switch string(bytes.TrimRight(line, " \t\r\n")) {
+	case "---":
+		return YAML, []byte("---")
+	case "+++":
+		return TOML, []byte("+++")
+	}
+	return "", nil
+}
+
+// content returns the lines of src before the first line consisting of the
+// delimiter only, and whether there is such a line.
+func content(src, delim []byte) ([]byte, bool) {
+	for start := 0; start < len(src); {
+		end := len(src)
+		next := end
+		if i := bytes.IndexByte(src[start:], '\n'); i >= 0 {
+			end = start + i
+			next = end + 1
+		}
+		if bytes.Equal(bytes.TrimRight(src[start:end], " \t\r"), delim) {
+			return src[:start], true
+		}
+		start = next
+	}
+	return nil, false
+}
+
+func (p *frontMatterParser) Trigger() []byte {
+	return []byte{'-', '+'}
+}
+
+func (p *frontMatterParser) Open(parent ast.Node, reader text.Reader, _ parser.Context) (ast.Node, parser.State) {
+	if parent.Kind() != ast.KindDocument || parent.HasChildren() {
+		return nil, parser.NoChildren
+	}
+	if line, _ := reader.Position(); line != 0 {
+		return nil, parser.NoChildren
+	}
+
+	line, segment := reader.PeekLine()
+	format, delim := delimiter(line)
+	if format == "" {
+		return nil, parser.NoChildren
+	}
+	b, ok := content(reader.Source()[segment.Stop:], delim)
+	if !ok {
+		return nil, parser.NoChildren
+	}
+	// a thematic break and Markdown text aren't front matter, so leave
+	// blocks that don't parse to the other block parsers
+	values, err := Parse(format, b)
+	if err != nil {
+		return nil, parser.NoChildren
+	}
+
+	reader.Advance(segment.Len() - 1)
+	return &FrontMatter{Format: format, Values: values}, parser.NoChildren
+}
+
+func (p *frontMatterParser) Continue(node ast.Node, reader text.Reader, _ parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if format, _ := delimiter(line); format == node.(*FrontMatter).Format {
+		reader.Advance(segment.Len())
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *frontMatterParser) Close(node ast.Node, _ text.Reader, _ parser.Context) {
+	n := node.(*FrontMatter)
+	if doc, ok := n.Parent().(*ast.Document); ok {
+		doc.SetMeta(n.Values)
+	}
+}
+
+func (p *frontMatterParser) CanInterruptParagraph() bool {
+	return false
+}
+
+func (p *frontMatterParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that parses YAML and TOML front matter.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(&frontMatterParser{}, 0),
+	))
+}
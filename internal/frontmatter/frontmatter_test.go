@@ -0,0 +1,253 @@
+package frontmatter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/frontmatter"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestFrontMatter(t *testing.T) {
+	tests := []struct {
+		in     string
+		format string
+		meta   map[string]interface{}
+	}{
+		{
+			"---\ntitle: Hello\n---\n# Doc",
+			frontmatter.YAML,
+			map[string]interface{}{"title": "Hello"},
+		},
+		{
+			"+++\ntitle = \"Hello\"\n+++\n# Doc",
+			frontmatter.TOML,
+			map[string]interface{}{"title": "Hello"},
+		},
+		{
+			"---\ndescription: |\n  Hello\n\n  world\n---\n# Doc",
+			frontmatter.YAML,
+			map[string]interface{}{"description": "Hello\n\nworld\n"},
+		},
+		{"---\ntitle: Hello\n\nno closing delimiter", "", nil},
+		{"# Doc\n\n---\ntitle: Hello\n---\n", "", nil},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(frontmatter.New()))
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tc.in)))
+			n, ok := doc.FirstChild().(*frontmatter.FrontMatter)
+			if ok != (tc.format != "") {
+				t.Fatalf("expected front matter %v, got %s", tc.format != "", doc.FirstChild().Kind())
+			}
+			if !ok {
+				return
+			}
+			if n.Format != tc.format {
+				t.Errorf("expected format %q, got %q", tc.format, n.Format)
+			}
+			if n.NextSibling() == nil || n.NextSibling().Kind() != ast.KindHeading {
+				t.Errorf("expected front matter to be followed by a heading")
+			}
+			if meta := doc.(*ast.Document).Meta(); !reflect.DeepEqual(meta, tc.meta) {
+				t.Errorf("expected meta %v, got %v", tc.meta, meta)
+			}
+		})
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	in := `# comment
+title: "Release: 1.0"
+draft: false
+weight: 3
+ratio: 0.5
+tags: [go, 'cli tools']
+authors:
+  - Jane
+  - John
+params:
+  toc: true
+  nested:
+    key: value # trailing comment
+empty:
+`
+	expected := map[string]interface{}{
+		"title":   "Release: 1.0",
+		"draft":   false,
+		"weight":  3,
+		"ratio":   0.5,
+		"tags":    []interface{}{"go", "cli tools"},
+		"authors": []interface{}{"Jane", "John"},
+		"params": map[string]interface{}{
+			"toc": true,
+			"nested": map[string]interface{}{
+				"key": "value",
+			},
+		},
+		"empty": nil,
+	}
+
+	v, err := frontmatter.Parse(frontmatter.YAML, []byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+
+	if _, err := frontmatter.Parse(frontmatter.YAML, []byte("title: a\n  b: c\n")); err == nil {
+		t.Error("expected error for invalid indentation")
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	in := `# comment
+title = "Release notes"
+draft = false
+weight = 1_000
+tags = ["go", 'cli']
+date = 2024-01-02
+
+[params.social]
+twitter = "charmcli" # trailing comment
+`
+	expected := map[string]interface{}{
+		"title":  "Release notes",
+		"draft":  false,
+		"weight": 1000,
+		"tags":   []interface{}{"go", "cli"},
+		"date":   "2024-01-02",
+		"params": map[string]interface{}{
+			"social": map[string]interface{}{
+				"twitter": "charmcli",
+			},
+		},
+	}
+
+	v, err := frontmatter.Parse(frontmatter.TOML, []byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+
+	if _, err := frontmatter.Parse(frontmatter.TOML, []byte("title\n")); err == nil {
+		t.Error("expected error for missing value")
+	}
+}
+
+func TestParseYAMLBlockScalars(t *testing.T) {
+	in := `description: |
+  First line
+  # not a comment
+
+  Third line
+summary: >-
+  Folded
+  into one line.
+
+  New paragraph.
+keep: |+
+  kept
+
+notes:
+  - |
+    item
+  - >
+    folded
+    item
+indented: |2
+    code
+title: After
+`
+	expected := map[string]interface{}{
+		"description": "First line\n# not a comment\n\nThird line\n",
+		"summary":     "Folded into one line.\nNew paragraph.",
+		"keep":        "kept\n\n",
+		"notes":       []interface{}{"item\n", "folded item\n"},
+		"indented":    "  code\n",
+		"title":       "After",
+	}
+
+	v, err := frontmatter.Parse(frontmatter.YAML, []byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %q, got %q", expected, v)
+	}
+}
+
+func TestParseTOMLMultiLine(t *testing.T) {
+	in := `tags = [
+  "go",   # language
+  "cli",
+]
+matrix = [
+  [1, 2],
+  [3, 4],
+]
+description = """
+Spans
+several # lines"""
+title = "After"
+`
+	expected := map[string]interface{}{
+		"tags":        []interface{}{"go", "cli"},
+		"matrix":      []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}},
+		"description": "Spans\nseveral # lines",
+		"title":       "After",
+	}
+
+	v, err := frontmatter.Parse(frontmatter.TOML, []byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+
+	if _, err := frontmatter.Parse(frontmatter.TOML, []byte("tags = [\n\"go\"\n")); err == nil {
+		t.Error("expected error for an unterminated array")
+	}
+}
+
+func TestNotFrontMatter(t *testing.T) {
+	// delimited blocks that don't parse are thematic breaks and text
+	tests := []struct {
+		in    string
+		kinds []ast.NodeKind
+	}{
+		{
+			"---\n\nIntro paragraph: with colon.\n\nSome text here.\n\n---\n\nMore text.\n",
+			[]ast.NodeKind{ast.KindThematicBreak, ast.KindParagraph, ast.KindParagraph, ast.KindThematicBreak, ast.KindParagraph},
+		},
+		{
+			"+++\nnot toml\n+++\n",
+			[]ast.NodeKind{ast.KindParagraph},
+		},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(frontmatter.New()))
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tc.in)))
+
+			var kinds []ast.NodeKind
+			for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+				kinds = append(kinds, n.Kind())
+			}
+			if !reflect.DeepEqual(kinds, tc.kinds) {
+				t.Errorf("expected %v, got %v", tc.kinds, kinds)
+			}
+			if meta := doc.(*ast.Document).Meta(); len(meta) != 0 {
+				t.Errorf("expected no meta, got %v", meta)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package frontmatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML parses the subset of TOML commonly found in front matter:
+// key/value pairs, tables, and string, number, boolean and array values.
+// Arrays and strings may span several lines.
+func parseTOML(b []byte) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	table := values
+
+	lines := strings.Split(string(b), "\n")
+	for i := 0; i < len(lines); i++ {
+		num := i + 1
+		l := strings.TrimSpace(stripComment(lines[i]))
+		if l == "" {
+			continue
+		}
+
+		if l[0] == '[' {
+			if l[len(l)-1] != ']' {
+				return nil, fmt.Errorf("toml: line %d: invalid table header", num)
+			}
+			var err error
+			table, err = tomlTable(values, l[1:len(l)-1])
+			if err != nil {
+				return nil, fmt.Errorf("toml: line %d: %w", num, err)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(l, "=")
+		if !ok {
+			return nil, fmt.Errorf("toml: line %d: expected key = value", num)
+		}
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if delim := value[:min(len(value), 3)]; delim == `"""` || delim == "'''" {
+			// the raw line, as a multi-line string may contain a '#'
+			_, value, _ = strings.Cut(lines[i], "=")
+			value = strings.TrimLeft(value, " \t")[3:]
+			for !strings.Contains(value, delim) {
+				if i++; i == len(lines) {
+					return nil, fmt.Errorf("toml: line %d: unterminated string", num)
+				}
+				value += "\n" + strings.TrimRight(lines[i], "\r")
+			}
+			value, _, _ = strings.Cut(value, delim)
+			table[key] = strings.TrimPrefix(value, "\n")
+			continue
+		}
+
+		for strings.HasPrefix(value, "[") && !balanced(value) {
+			if i++; i == len(lines) {
+				return nil, fmt.Errorf("toml: line %d: unterminated array", num)
+			}
+			value += " " + strings.TrimSpace(stripComment(lines[i]))
+		}
+		v, err := tomlValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("toml: line %d: %w", num, err)
+		}
+		table[key] = v
+	}
+	return values, nil
+}
+
+// balanced returns whether the brackets of s, outside of quotes, are closed.
+func balanced(s string) bool {
+	var quote rune
+	depth := 0
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// tomlTable returns the (possibly dotted) table name, creating it if needed.
+func tomlTable(root map[string]interface{}, name string) (map[string]interface{}, error) {
+	table := root
+	for _, part := range strings.Split(name, ".") {
+		part = unquote(strings.TrimSpace(part))
+		if part == "" {
+			return nil, fmt.Errorf("invalid table name %q", name)
+		}
+		switch v := table[part].(type) {
+		case nil:
+			m := map[string]interface{}{}
+			table[part] = m
+			table = m
+		case map[string]interface{}:
+			table = v
+		default:
+			return nil, fmt.Errorf("key %q is not a table", part)
+		}
+	}
+	return table, nil
+}
+
+func tomlValue(s string) (interface{}, error) {
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("missing value")
+	case s[0] == '[':
+		if s[len(s)-1] != ']' {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		items := []interface{}{}
+		for _, item := range splitList(s[1 : len(s)-1]) {
+			v, err := tomlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case s[0] == '"' || s[0] == '\'':
+		if !isQuoted(s) {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		if s[0] == '\'' {
+			return s[1 : len(s)-1], nil
+		}
+		return unquote(s), nil
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	}
+
+	n := strings.ReplaceAll(s, "_", "")
+	if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+		return int(i), nil
+	}
+	if f, err := strconv.ParseFloat(n, 64); err == nil {
+		return f, nil
+	}
+	// Dates and times are kept as strings.
+	return s, nil
+}
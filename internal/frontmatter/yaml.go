@@ -0,0 +1,327 @@
+package frontmatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a line of YAML front matter. Blank and comment lines have no
+// text, but are kept for block scalars.
+type yamlLine struct {
+	indent int
+	text   string
+	raw    string
+	num    int
+}
+
+// parseYAML parses the subset of YAML commonly found in front matter:
+// mappings, block and flow sequences, plain or quoted scalars, and literal
+// (|) or folded (>) block scalars.
+func parseYAML(b []byte) (map[string]interface{}, error) {
+	var lines []yamlLine
+	for i, l := range strings.Split(string(b), "\n") {
+		l = strings.TrimRight(l, " \t\r")
+		trimmed := strings.TrimLeft(l, " ")
+		line := yamlLine{
+			indent: len(l) - len(trimmed),
+			raw:    l,
+			num:    i + 1,
+		}
+		if !strings.HasPrefix(trimmed, "#") {
+			line.text = trimmed
+		}
+		lines = append(lines, line)
+	}
+
+	p := &yamlParser{lines: lines}
+	values := map[string]interface{}{}
+	p.skip()
+	if p.pos == len(p.lines) {
+		return values, nil
+	}
+	if err := p.mapping(values, p.lines[p.pos].indent); err != nil {
+		return nil, err
+	}
+	if p.skip(); p.pos < len(p.lines) {
+		return nil, fmt.Errorf("yaml: line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return values, nil
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// skip skips blank and comment lines.
+func (p *yamlParser) skip() {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+}
+
+func (p *yamlParser) mapping(m map[string]interface{}, indent int) error {
+	for p.skip(); p.pos < len(p.lines); p.skip() {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			return nil
+		}
+		if l.indent > indent {
+			return fmt.Errorf("yaml: line %d: unexpected indentation", l.num)
+		}
+
+		key, value, ok := strings.Cut(l.text, ":")
+		if !ok || (value != "" && value[0] != ' ' && value[0] != '\t') {
+			return fmt.Errorf("yaml: line %d: expected key: value", l.num)
+		}
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(stripComment(value))
+		p.pos++
+
+		if isBlockScalar(value) {
+			v, err := p.blockScalar(value, indent)
+			if err != nil {
+				return err
+			}
+			m[key] = v
+			continue
+		}
+		if value != "" {
+			m[key] = yamlScalar(value)
+			continue
+		}
+		v, err := p.nested(indent)
+		if err != nil {
+			return err
+		}
+		m[key] = v
+	}
+	return nil
+}
+
+// nested parses the block value of a key at the given indentation, which is
+// either a mapping or a sequence.
+func (p *yamlParser) nested(indent int) (interface{}, error) {
+	if p.skip(); p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	l := p.lines[p.pos]
+	isSeq := l.text == "-" || strings.HasPrefix(l.text, "- ")
+	switch {
+	case isSeq && l.indent >= indent:
+		return p.sequence(l.indent)
+	case l.indent > indent:
+		m := map[string]interface{}{}
+		return m, p.mapping(m, l.indent)
+	}
+	return nil, nil
+}
+
+func (p *yamlParser) sequence(indent int) ([]interface{}, error) {
+	var s []interface{}
+	for p.skip(); p.pos < len(p.lines); p.skip() {
+		l := p.lines[p.pos]
+		if l.indent != indent || (l.text != "-" && !strings.HasPrefix(l.text, "- ")) {
+			break
+		}
+		item := strings.TrimSpace(stripComment(strings.TrimPrefix(l.text, "-")))
+		p.pos++
+		if isBlockScalar(item) {
+			v, err := p.blockScalar(item, indent)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+			continue
+		}
+		if item != "" {
+			s = append(s, yamlScalar(item))
+			continue
+		}
+		v, err := p.nested(indent)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, v)
+	}
+	return s, nil
+}
+
+func isBlockScalar(s string) bool {
+	return s != "" && (s[0] == '|' || s[0] == '>')
+}
+
+// blockScalar parses a literal (|) or folded (>) block scalar with the given
+// header, such as "|" or ">-", whose lines are indented deeper than indent.
+func (p *yamlParser) blockScalar(header string, indent int) (string, error) {
+	num := p.lines[p.pos-1].num
+	var chomp rune
+	contentIndent := 0
+	for _, r := range header[1:] {
+		switch {
+		case r == '-' || r == '+':
+			chomp = r
+		case r >= '1' && r <= '9':
+			contentIndent = indent + int(r-'0')
+		default:
+			return "", fmt.Errorf("yaml: line %d: invalid block scalar header %q", num, header)
+		}
+	}
+
+	var lines []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		l := p.lines[p.pos]
+		if strings.TrimSpace(l.raw) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if contentIndent == 0 {
+			contentIndent = l.indent
+		}
+		if l.indent <= indent || l.indent < contentIndent {
+			break
+		}
+		lines = append(lines, l.raw[contentIndent:])
+	}
+
+	// trailing blank lines are only kept by "+" chomping
+	end := len(lines)
+	for end > 0 && lines[end-1] == "" {
+		end--
+	}
+	trailing := len(lines) - end
+	lines = lines[:end]
+
+	var s string
+	if header[0] == '|' {
+		s = strings.Join(lines, "\n")
+	} else {
+		s = foldYAML(lines)
+	}
+	switch {
+	case chomp == '-' || len(lines) == 0:
+	case chomp == '+':
+		s += strings.Repeat("\n", trailing+1)
+	default:
+		s += "\n"
+	}
+	return s, nil
+}
+
+// foldYAML joins the lines of a folded block scalar: line breaks between
+// lines become spaces, blank lines become line breaks, and more indented
+// lines are kept as they are.
+func foldYAML(lines []string) string {
+	var b strings.Builder
+	for i, l := range lines {
+		if l == "" {
+			b.WriteString("\n")
+			continue
+		}
+		if i > 0 && lines[i-1] != "" {
+			if moreIndented(l) || moreIndented(lines[i-1]) {
+				b.WriteString("\n")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(l)
+	}
+	return b.String()
+}
+
+func moreIndented(l string) bool {
+	return strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")
+}
+
+func yamlScalar(s string) interface{} {
+	if len(s) >= 2 && s[0] == '[' && s[len(s)-1] == ']' {
+		var items []interface{}
+		for _, item := range splitList(s[1 : len(s)-1]) {
+			items = append(items, yamlScalar(item))
+		}
+		return items
+	}
+	if isQuoted(s) {
+		return unquote(s)
+	}
+	switch s {
+	case "true", "True", "TRUE", "yes", "Yes":
+		return true
+	case "false", "False", "FALSE", "no", "No":
+		return false
+	case "null", "Null", "NULL", "~":
+		return nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return int(i)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+// stripComment removes a trailing " #" comment outside of quotes.
+func stripComment(s string) string {
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// splitList splits the items of a flow sequence or TOML array on commas
+// outside of quotes and brackets.
+func splitList(s string) []string {
+	var items []string
+	var quote rune
+	depth, start := 0, 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		items = append(items, last)
+	}
+	return items
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]
+}
+
+func unquote(s string) string {
+	if !isQuoted(s) {
+		return s
+	}
+	if s[0] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+}
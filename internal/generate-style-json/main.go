@@ -26,6 +26,13 @@ func writeStyleJSON(filename string, styleConfig *ansi.StyleConfig) error {
 }
 
 func run() error {
+	// the exported styles keep their palette references if they don't
+	// resolve
+	for style, styleConfig := range styles.DefaultStyleTemplates {
+		if _, err := ansi.ResolvePalette(*styleConfig, nil); err != nil {
+			return fmt.Errorf("%s: %w", style, err)
+		}
+	}
 	for style, styleConfig := range styles.DefaultStyles {
 		if err := writeStyleJSON(filepath.Join("styles", style+".json"), styleConfig); err != nil {
 			return err
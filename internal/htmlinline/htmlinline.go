@@ -0,0 +1,228 @@
+// Package htmlinline provides a goldmark extension that turns pairs of inline
+// HTML tags into nodes, so their contents can be styled: <b>, <i>, <del>,
+// <code> and <a> become their Markdown equivalents, <img> an image, and
+// tags without one, like <kbd> or <sup>, Tag nodes.
+package htmlinline
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	astext "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/net/html"
+)
+
+// KindTag is the NodeKind of a Tag node.
+var KindTag = ast.NewNodeKind("HTMLTag")
+
+// Tag is an inline node holding the contents of an HTML element. Void
+// elements like <br> have no children.
+type Tag struct {
+	ast.BaseInline
+
+	// Name is the element's lower case tag name.
+	Name string
+
+	// Attrs holds the element's attributes.
+	Attrs map[string]string
+}
+
+// Kind implements ast.Node.Kind.
+func (n *Tag) Kind() ast.NodeKind {
+	return KindTag
+}
+
+// Dump implements ast.Node.Dump.
+func (n *Tag) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Name": n.Name,
+	}, nil)
+}
+
+// Tags lists the elements turned into Tag nodes.
+var Tags = map[string]bool{
+	"kbd":   true,
+	"sub":   true,
+	"sup":   true,
+	"mark":  true,
+	"ins":   true,
+	"u":     true,
+	"small": true,
+	"abbr":  true,
+	"q":     true,
+	"cite":  true,
+	"var":   true,
+	"samp":  true,
+	"span":  true,
+}
+
+// markdown maps elements to constructors of their Markdown equivalents.
+var markdown = map[string]func(attrs map[string]string) ast.Node{
+	"b":      func(map[string]string) ast.Node { return ast.NewEmphasis(2) }, //nolint: mnd
+	"strong": func(map[string]string) ast.Node { return ast.NewEmphasis(2) }, //nolint: mnd
+	"i":      func(map[string]string) ast.Node { return ast.NewEmphasis(1) },
+	"em":     func(map[string]string) ast.Node { return ast.NewEmphasis(1) },
+	"s":      func(map[string]string) ast.Node { return astext.NewStrikethrough() },
+	"del":    func(map[string]string) ast.Node { return astext.NewStrikethrough() },
+	"strike": func(map[string]string) ast.Node { return astext.NewStrikethrough() },
+	"code":   func(map[string]string) ast.Node { return ast.NewCodeSpan() },
+	"a": func(attrs map[string]string) ast.Node {
+		if attrs["href"] == "" {
+			return nil
+		}
+		link := ast.NewLink()
+		link.Destination = []byte(attrs["href"])
+		link.Title = []byte(attrs["title"])
+		return link
+	},
+}
+
+// tag is a parsed HTML tag.
+type tag struct {
+	name    string
+	attrs   map[string]string
+	closing bool
+	void    bool
+}
+
+// parseTag parses a raw HTML tag, reporting false for comments, processing
+// instructions and the like.
+func parseTag(s string) (tag, bool) {
+	z := html.NewTokenizer(strings.NewReader(s))
+	tt := z.Next()
+	if tt != html.StartTagToken && tt != html.EndTagToken && tt != html.SelfClosingTagToken {
+		return tag{}, false
+	}
+	tok := z.Token()
+	t := tag{
+		name:    tok.Data,
+		attrs:   map[string]string{},
+		closing: tt == html.EndTagToken,
+		void:    tt == html.SelfClosingTagToken,
+	}
+	for _, a := range tok.Attr {
+		t.attrs[a.Key] = a.Val
+	}
+	switch t.name {
+	case "br", "img", "wbr":
+		t.void = true
+	}
+	return t, true
+}
+
+type transformer struct{}
+
+// Transform replaces pairs of inline HTML tags, and void tags, with nodes.
+// Unknown and unpaired tags are left as they are.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	process(doc, reader.Source())
+}
+
+func process(parent ast.Node, source []byte) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		if raw, ok := n.(*ast.RawHTML); ok {
+			if r := replace(parent, raw, source); r != nil {
+				n = r
+			}
+		}
+		if n.HasChildren() {
+			process(n, source)
+		}
+	}
+}
+
+// replace replaces a raw HTML tag, and its contents up to the closing tag,
+// with a node. It returns nil if the tag is left as it is.
+func replace(parent ast.Node, raw *ast.RawHTML, source []byte) ast.Node {
+	open, ok := parseTag(string(raw.Segments.Value(source)))
+	if !ok || open.closing {
+		return nil
+	}
+
+	if open.void {
+		var node ast.Node
+		switch open.name {
+		case "br":
+			node = &Tag{Name: open.name, Attrs: open.attrs}
+		case "img":
+			link := ast.NewLink()
+			link.Destination = []byte(open.attrs["src"])
+			link.Title = []byte(open.attrs["title"])
+			img := ast.NewImage(link)
+			if alt := open.attrs["alt"]; alt != "" {
+				img.AppendChild(img, ast.NewString([]byte(alt)))
+			}
+			node = img
+		default:
+			node = ast.NewString(nil)
+		}
+		parent.ReplaceChild(parent, raw, node)
+		return node
+	}
+
+	var node ast.Node
+	if newNode, ok := markdown[open.name]; ok {
+		node = newNode(open.attrs)
+	} else if Tags[open.name] {
+		node = &Tag{Name: open.name, Attrs: open.attrs}
+	}
+	if node == nil {
+		return nil
+	}
+
+	end := closingTag(raw, open.name, source)
+	if end == nil {
+		return nil
+	}
+	for n := raw.NextSibling(); n != end; {
+		next := n.NextSibling()
+		parent.RemoveChild(parent, n)
+		node.AppendChild(node, n)
+		n = next
+	}
+	parent.RemoveChild(parent, end)
+	parent.ReplaceChild(parent, raw, node)
+	return node
+}
+
+// closingTag returns the sibling closing the element opened by start.
+func closingTag(start *ast.RawHTML, name string, source []byte) ast.Node {
+	depth := 0
+	for n := start.NextSibling(); n != nil; n = n.NextSibling() {
+		raw, ok := n.(*ast.RawHTML)
+		if !ok {
+			continue
+		}
+		t, ok := parseTag(string(raw.Segments.Value(source)))
+		if !ok || t.name != name || t.void {
+			continue
+		}
+		if !t.closing {
+			depth++
+			continue
+		}
+		if depth == 0 {
+			return n
+		}
+		depth--
+	}
+	return nil
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that turns inline HTML tags into nodes.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&transformer{}, 100), //nolint: mnd
+	))
+}
@@ -0,0 +1,56 @@
+package htmlinline_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/htmlinline"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"kbd", "Press <kbd>Ctrl</kbd>", "Text HTMLTag(kbd)[Text]"},
+		{"nested", "<sup><b>x</b></sup>", "HTMLTag(sup)[Emphasis[Text]]"},
+		{"bold", "<b>a</b> and <i>b</i>", "Emphasis[Text] Text Emphasis[Text]"},
+		{"link", `<a href="https://example.com">a</a>`, "Link[Text]"},
+		{"anchor", `<a name="x">a</a>`, "RawHTML Text RawHTML"},
+		{"br", "a<br>b", "Text HTMLTag(br) Text"},
+		{"img", `a <img src="a.png" alt="A">`, "Text Image[String]"},
+		{"unpaired", "<kbd>a", "RawHTML Text"},
+		{"unknown", "<foo>a</foo>", "RawHTML Text RawHTML"},
+		{"comment", "a <!-- b -->", "Text RawHTML"},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(htmlinline.New()))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tt.in)))
+			if got := dump(doc.FirstChild()); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// dump describes the children of n.
+func dump(n ast.Node) string {
+	var parts []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		s := c.Kind().String()
+		if tag, ok := c.(*htmlinline.Tag); ok {
+			s += "(" + tag.Name + ")"
+		}
+		if c.HasChildren() {
+			s += "[" + dump(c) + "]"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,396 @@
+// Package htmltable provides a goldmark extension that replaces HTML <table>
+// blocks with GFM table nodes, so they're rendered like Markdown tables.
+package htmltable
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	astext "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type transformer struct{}
+
+// Transform replaces every HTML block starting with a <table> element with a
+// Table node. Tables containing blank lines are split into several blocks by
+// the parser; they're joined up to the block closing the table.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.HTMLBlock
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if b, ok := n.(*ast.HTMLBlock); ok && entering && startsTable(b, source) {
+			blocks = append(blocks, b)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, b := range blocks {
+		start, stop, last := tableSource(b, source)
+		table := Parse(source[start:stop])
+		if table == nil {
+			continue
+		}
+
+		parent := b.Parent()
+		for n := b.NextSibling(); n != nil && last != b; {
+			next := n.NextSibling()
+			parent.RemoveChild(parent, n)
+			if n == last {
+				break
+			}
+			n = next
+		}
+		parent.ReplaceChild(parent, b, table)
+	}
+}
+
+func startsTable(b *ast.HTMLBlock, source []byte) bool {
+	if b.Lines().Len() == 0 {
+		return false
+	}
+	seg := b.Lines().At(0)
+	line := bytes.TrimSpace(seg.Value(source))
+	return len(line) >= 6 && strings.EqualFold(string(line[:6]), "<table")
+}
+
+// tableSource returns the range of the source holding the table started by
+// b, and the last block it spans. A table that's never closed spans b only,
+// so the blocks following it are kept.
+func tableSource(b *ast.HTMLBlock, source []byte) (int, int, ast.Node) {
+	start := b.Lines().At(0).Start
+	for n := ast.Node(b); n != nil; n = n.NextSibling() {
+		stop := blockStop(n)
+		if bytes.Contains(bytes.ToLower(source[start:stop]), []byte("</table>")) {
+			return start, stop, n
+		}
+	}
+	return start, blockStop(b), b
+}
+
+// blockStop returns the end of a block's source.
+func blockStop(n ast.Node) int {
+	if b, ok := n.(*ast.HTMLBlock); ok && b.HasClosure() {
+		return b.ClosureLine.Stop
+	}
+	if l := n.Lines(); l != nil && l.Len() > 0 {
+		return l.At(l.Len() - 1).Stop
+	}
+	stop := 0
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		stop = max(stop, blockStop(c))
+	}
+	return stop
+}
+
+// Parse parses an HTML table into a Table node. Header rows are the rows of
+// <thead> or rows consisting of <th> cells only. Cells spanning several
+// columns are followed by empty cells. It returns nil if src holds no table.
+func Parse(src []byte) *astext.Table {
+	nodes, err := html.ParseFragment(bytes.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return nil
+	}
+	var tbl *html.Node
+	for _, n := range nodes {
+		if tbl = find(n, atom.Table); tbl != nil {
+			break
+		}
+	}
+	if tbl == nil {
+		return nil
+	}
+
+	var head, body []*html.Node
+	var collect func(n *html.Node, inHead bool)
+	collect = func(n *html.Node, inHead bool) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.DataAtom { //nolint:exhaustive
+			case atom.Thead:
+				collect(c, true)
+			case atom.Tbody, atom.Tfoot:
+				collect(c, false)
+			case atom.Tr:
+				if inHead || (len(body) == 0 && headerRow(c)) {
+					head = append(head, c)
+				} else {
+					body = append(body, c)
+				}
+			}
+		}
+	}
+	collect(tbl, false)
+
+	// the table has as many columns as its row with the most cells, spans
+	// only stretch cells across those
+	rows := append(append([]*html.Node{}, head...), body...)
+	var columns int
+	for _, r := range rows {
+		columns = max(columns, len(cells(r)))
+	}
+
+	// the columns' alignment comes from the widest row
+	var alignments []astext.Alignment
+	for _, r := range rows {
+		var align []astext.Alignment
+		for _, c := range cells(r) {
+			for range colspan(c, columns-len(align)) {
+				align = append(align, alignment(c))
+			}
+		}
+		if len(align) > len(alignments) {
+			alignments = align
+		}
+	}
+	if columns == 0 {
+		return nil
+	}
+
+	table := astext.NewTable()
+	table.Alignments = alignments
+
+	header := astext.NewTableHeader(row(head, columns, alignments))
+	table.AppendChild(table, header)
+	for _, r := range body {
+		table.AppendChild(table, row([]*html.Node{r}, columns, alignments))
+	}
+	return table
+}
+
+// row converts HTML rows into a single table row. Multiple header rows are
+// merged, joining their cells' contents.
+func row(rows []*html.Node, columns int, alignments []astext.Alignment) *astext.TableRow {
+	contents := make([][]ast.Node, columns)
+	for _, r := range rows {
+		var col int
+		for _, c := range cells(r) {
+			if col >= columns {
+				break
+			}
+			inlines := inline(c)
+			if len(contents[col]) > 0 && len(inlines) > 0 {
+				contents[col] = append(contents[col], ast.NewString([]byte(" ")))
+			}
+			contents[col] = append(contents[col], inlines...)
+			col += colspan(c, columns-col)
+		}
+	}
+
+	tr := astext.NewTableRow(alignments)
+	for col := range columns {
+		cell := astext.NewTableCell()
+		cell.Alignment = alignments[col]
+		for _, n := range trimSpace(contents[col]) {
+			cell.AppendChild(cell, n)
+		}
+		tr.AppendChild(tr, cell)
+	}
+	return tr
+}
+
+// inline converts the contents of an HTML element into inline nodes.
+func inline(n *html.Node) []ast.Node {
+	var nodes []ast.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type { //nolint:exhaustive
+		case html.TextNode:
+			s := strings.Join(strings.Fields(c.Data), " ")
+			if s == "" && c.Data != "" {
+				s = " "
+			} else if s != "" {
+				if strings.TrimLeft(c.Data, " \t\r\n") != c.Data {
+					s = " " + s
+				}
+				if strings.TrimRight(c.Data, " \t\r\n") != c.Data {
+					s += " "
+				}
+			}
+			if s != "" {
+				nodes = append(nodes, ast.NewString([]byte(s)))
+			}
+		case html.ElementNode:
+			nodes = append(nodes, element(c)...)
+		}
+	}
+	return nodes
+}
+
+// element converts an inline HTML element into inline nodes.
+func element(n *html.Node) []ast.Node {
+	wrap := func(parent ast.Node) []ast.Node {
+		for _, c := range inline(n) {
+			parent.AppendChild(parent, c)
+		}
+		return []ast.Node{parent}
+	}
+
+	switch n.DataAtom { //nolint:exhaustive
+	case atom.B, atom.Strong:
+		return wrap(ast.NewEmphasis(2)) //nolint:mnd
+	case atom.I, atom.Em:
+		return wrap(ast.NewEmphasis(1))
+	case atom.S, atom.Del, atom.Strike:
+		return wrap(astext.NewStrikethrough())
+	case atom.Code, atom.Kbd, atom.Samp:
+		code := ast.NewCodeSpan()
+		code.AppendChild(code, ast.NewString([]byte(textContent(n))))
+		return []ast.Node{code}
+	case atom.A:
+		href := attr(n, "href")
+		if href == "" {
+			return inline(n)
+		}
+		link := ast.NewLink()
+		link.Destination = []byte(href)
+		link.Title = []byte(attr(n, "title"))
+		return wrap(link)
+	case atom.Img:
+		link := ast.NewLink()
+		link.Destination = []byte(attr(n, "src"))
+		link.Title = []byte(attr(n, "title"))
+		img := ast.NewImage(link)
+		if alt := attr(n, "alt"); alt != "" {
+			img.AppendChild(img, ast.NewString([]byte(alt)))
+		}
+		return []ast.Node{img}
+	case atom.Br:
+		return []ast.Node{ast.NewString([]byte(" "))}
+	case atom.Script, atom.Style:
+		return nil
+	}
+	return inline(n)
+}
+
+// trimSpace removes leading and trailing whitespace strings.
+func trimSpace(nodes []ast.Node) []ast.Node {
+	for len(nodes) > 0 {
+		s, ok := nodes[0].(*ast.String)
+		if !ok {
+			break
+		}
+		s.Value = bytes.TrimLeft(s.Value, " ")
+		if len(s.Value) > 0 {
+			break
+		}
+		nodes = nodes[1:]
+	}
+	for len(nodes) > 0 {
+		s, ok := nodes[len(nodes)-1].(*ast.String)
+		if !ok {
+			break
+		}
+		s.Value = bytes.TrimRight(s.Value, " ")
+		if len(s.Value) > 0 {
+			break
+		}
+		nodes = nodes[:len(nodes)-1]
+	}
+	return nodes
+}
+
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if f := find(c, a); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func cells(tr *html.Node) []*html.Node {
+	var cells []*html.Node
+	for c := tr.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == atom.Td || c.DataAtom == atom.Th {
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
+
+func headerRow(tr *html.Node) bool {
+	cells := cells(tr)
+	for _, c := range cells {
+		if c.DataAtom != atom.Th {
+			return false
+		}
+	}
+	return len(cells) > 0
+}
+
+// colspan returns the number of columns a cell spans, at most limit.
+func colspan(n *html.Node, limit int) int {
+	span, err := strconv.Atoi(attr(n, "colspan"))
+	if err != nil || span < 1 {
+		return min(1, limit)
+	}
+	return min(span, limit)
+}
+
+func alignment(n *html.Node) astext.Alignment {
+	align := attr(n, "align")
+	for _, decl := range strings.Split(attr(n, "style"), ";") {
+		k, v, ok := strings.Cut(decl, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(k), "text-align") {
+			align = strings.TrimSpace(v)
+		}
+	}
+	switch strings.ToLower(align) {
+	case "left":
+		return astext.AlignLeft
+	case "center":
+		return astext.AlignCenter
+	case "right":
+		return astext.AlignRight
+	}
+	return astext.AlignNone
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that renders HTML tables like Markdown
+// tables.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&transformer{}, 100), //nolint: mnd
+	))
+}
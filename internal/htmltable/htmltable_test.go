@@ -0,0 +1,144 @@
+package htmltable_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/htmltable"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	astext "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestParse(t *testing.T) {
+	src := `<table>
+<tr><th>Name</th><th style="text-align: center">Kind</th><th align="right">Size</th></tr>
+<tr><td>a <b>bold</b> name</td><td colspan="2">spanned</td></tr>
+<tr><td><a href="https://example.com">link</a></td><td><code>x &lt; y</code></td></tr>
+</table>`
+
+	table := htmltable.Parse([]byte(src))
+	if table == nil {
+		t.Fatal("expected a table")
+	}
+
+	wantAlign := []astext.Alignment{astext.AlignNone, astext.AlignCenter, astext.AlignRight}
+	if !reflect.DeepEqual(table.Alignments, wantAlign) {
+		t.Errorf("expected alignments %v, got %v", wantAlign, table.Alignments)
+	}
+
+	want := [][]string{
+		{"Name", "Kind", "Size"},
+		{"a bold name", "spanned", ""},
+		{"link", "x < y", ""},
+	}
+	var got [][]string
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, cellText(cell))
+		}
+		got = append(got, cells)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if table.FirstChild().Kind() != astext.KindTableHeader {
+		t.Errorf("expected the first row to be the header")
+	}
+}
+
+func TestParseNoTable(t *testing.T) {
+	if table := htmltable.Parse([]byte("<div>no table</div>")); table != nil {
+		t.Errorf("expected nil, got %v", table)
+	}
+}
+
+func TestParseColspan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{
+			name: "single cell",
+			src:  `<table><tr><td colspan="99999">wide</td></tr></table>`,
+			want: 1,
+		},
+		{
+			name: "wider than the table",
+			src:  `<table><tr><td>a</td><td>b</td><td>c</td></tr><tr><td colspan="99999">wide</td></tr></table>`,
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := htmltable.Parse([]byte(tt.src))
+			if table == nil {
+				t.Fatal("expected a table")
+			}
+			if n := len(table.Alignments); n != tt.want {
+				t.Errorf("expected %d columns, got %d", tt.want, n)
+			}
+			for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+				if n := row.ChildCount(); n != tt.want {
+					t.Errorf("expected %d cells, got %d", tt.want, n)
+				}
+			}
+		})
+	}
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		kinds []ast.NodeKind
+	}{
+		{
+			name:  "table",
+			src:   "<table>\n<tr><td>a</td></tr>\n</table>\n\nparagraph\n",
+			kinds: []ast.NodeKind{astext.KindTable, ast.KindParagraph},
+		},
+		{
+			name:  "table with blank lines",
+			src:   "<table>\n<tr><td>a</td></tr>\n\n<tr><td>b</td></tr>\n</table>\n\nparagraph\n",
+			kinds: []ast.NodeKind{astext.KindTable, ast.KindParagraph},
+		},
+		{
+			name:  "unclosed table",
+			src:   "<table>\n<tr><td>a</td></tr>\n\n# Heading kept?\n\nparagraph\n",
+			kinds: []ast.NodeKind{astext.KindTable, ast.KindHeading, ast.KindParagraph},
+		},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(htmltable.New()))
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := []byte(tc.src)
+			doc := md.Parser().Parse(text.NewReader(src))
+
+			var kinds []ast.NodeKind
+			for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+				kinds = append(kinds, n.Kind())
+			}
+			if !reflect.DeepEqual(kinds, tc.kinds) {
+				t.Errorf("expected %v, got %v", tc.kinds, kinds)
+			}
+		})
+	}
+}
+
+func cellText(n ast.Node) string {
+	if s, ok := n.(*ast.String); ok {
+		return string(s.Value)
+	}
+	var b []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		b = append(b, cellText(c)...)
+	}
+	return string(b)
+}
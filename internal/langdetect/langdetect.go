@@ -0,0 +1,204 @@
+// Package langdetect guesses the programming language of a code snippet.
+package langdetect
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// A signal is a pattern hinting at a language, weighted by how certain the
+// hint is.
+type signal struct {
+	re     *regexp.Regexp
+	weight float32
+}
+
+func sig(pattern string, weight float32) signal {
+	return signal{
+		re:     regexp.MustCompile(`(?m)` + pattern),
+		weight: weight,
+	}
+}
+
+// heuristics maps languages to the signals hinting at them.
+var heuristics = map[string][]signal{
+	"go": {
+		sig(`^package \w+$`, 0.5),
+		sig(`^func (\(\w+ \*?\w+\) )?\w+\(`, 0.4),
+		sig(`^import \($`, 0.4),
+		sig(`\w+ := `, 0.2),
+	},
+	"python": {
+		sig(`^\s*def \w+\(.*\):\s*$`, 0.5),
+		sig(`^\s*class \w+(\(.*\))?:\s*$`, 0.4),
+		sig(`^from [\w.]+ import \w+`, 0.4),
+		sig(`^if __name__ == ['"]__main__['"]:`, 0.6),
+		sig(`^\s*print\(`, 0.2),
+	},
+	"javascript": {
+		sig(`^\s*(const|let|var) \w+ = `, 0.3),
+		sig(`console\.log\(`, 0.5),
+		sig(`^\s*function \w+\(`, 0.3),
+		sig(`require\(['"]`, 0.4),
+		sig(`=> \{?`, 0.1),
+	},
+	"typescript": {
+		sig(`^\s*(export )?interface \w+ \{`, 0.5),
+		sig(`: (string|number|boolean)(\[\])?[,;)=]`, 0.4),
+	},
+	"rust": {
+		sig(`^\s*(pub )?fn \w+`, 0.4),
+		sig(`\blet mut \w+`, 0.4),
+		sig(`\bprintln!\(`, 0.5),
+		sig(`^use \w+(::\w+)+`, 0.4),
+	},
+	"c": {
+		sig(`^#include [<"]`, 0.6),
+		sig(`^int main\(`, 0.3),
+	},
+	"java": {
+		sig(`\bpublic (static )?(final )?(class|void|interface) `, 0.5),
+		sig(`System\.out\.print`, 0.5),
+	},
+	"ruby": {
+		sig(`^\s*def \w+[^:]*$`, 0.2),
+		sig(`^\s*end$`, 0.2),
+		sig(`\bdo \|\w+\|`, 0.4),
+		sig(`^\s*puts `, 0.3),
+		sig(`^require ['"]`, 0.3),
+	},
+	"bash": {
+		sig(`^\s*(echo|export|sudo|cd|apt|apt-get|brew|npm|pip|go|git|docker|curl|make|mkdir|rm) `, 0.4),
+		sig(`^\s*(fi|done|esac)$`, 0.4),
+		sig(`; then$`, 0.4),
+	},
+	"html": {
+		sig(`(?i)<!DOCTYPE html>`, 1.0),
+		sig(`<(html|head|body|div|span|p|a|ul|li)( [^>]*)?>`, 0.5),
+	},
+	"xml": {
+		sig(`^<\?xml `, 1.0),
+	},
+	"php": {
+		sig(`^<\?php`, 1.0),
+	},
+	"sql": {
+		sig(`(?i)^\s*(select .+ from|insert into|create (table|index)|update \w+ set|delete from|alter table)\b`, 0.6),
+	},
+	"docker": {
+		sig(`^FROM \S+`, 0.5),
+		sig(`^(RUN|COPY|CMD|ENTRYPOINT|WORKDIR|ENV|EXPOSE) `, 0.4),
+	},
+	"css": {
+		sig(`^[.#]?[\w-]+( [.#]?[\w-]+)*\s*\{\s*$`, 0.3),
+		sig(`^\s+[\w-]+: [^;]+;$`, 0.3),
+	},
+	"hcl": {
+		sig(`^(resource|variable|provider|module|output|data|terraform)( "[\w-]+")* \{`, 0.6),
+	},
+	"toml": {
+		sig(`^\[[\w.-]+\]$`, 0.3),
+		sig(`^[\w-]+ = `, 0.3),
+	},
+	"yaml": {
+		sig(`^---$`, 0.2),
+		sig(`^[\w-]+:( [^{;]*)?$`, 0.3),
+		sig(`^\s+- [\w"']`, 0.2),
+	},
+}
+
+// interpreters maps shebang interpreters to languages.
+var interpreters = map[string]string{
+	"sh":      "bash",
+	"bash":    "bash",
+	"zsh":     "bash",
+	"dash":    "bash",
+	"ksh":     "bash",
+	"fish":    "fish",
+	"python":  "python",
+	"node":    "javascript",
+	"deno":    "typescript",
+	"ruby":    "ruby",
+	"perl":    "perl",
+	"php":     "php",
+	"lua":     "lua",
+	"pwsh":    "powershell",
+	"Rscript": "r",
+}
+
+// Detect guesses the language of the given code and returns its name along
+// with a confidence between 0 and 1. It returns an empty name if the
+// language can't be guessed.
+//
+// A shebang line determines the language with full confidence. Otherwise,
+// the code is matched against simple heuristics and analyzed by Chroma's
+// lexers, and the most confident guess wins.
+func Detect(code string) (string, float32) {
+	if lang := shebang(code); lang != "" {
+		return lang, 1
+	}
+
+	trimmed := strings.TrimSpace(code)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return "json", 1
+	}
+
+	var best string
+	var confidence float32
+	for lang, sigs := range heuristics {
+		var score float32
+		for _, s := range sigs {
+			if s.re.MatchString(code) {
+				score += s.weight
+			}
+		}
+		score = min(score, 1)
+		if score > confidence || score == confidence && score > 0 && lang < best {
+			best, confidence = lang, score
+		}
+	}
+
+	if l := lexers.Analyse(code); l != nil {
+		if a, ok := l.(chroma.Analyser); ok {
+			if score := a.AnalyseText(code); score > confidence {
+				best, confidence = strings.ToLower(l.Config().Name), score
+			}
+		}
+	}
+	return best, confidence
+}
+
+// shebang returns the language of the interpreter named in the code's
+// shebang line, e.g. "#!/usr/bin/env python3".
+func shebang(code string) string {
+	line, _, _ := strings.Cut(code, "\n")
+	line, ok := strings.CutPrefix(line, "#!")
+	if !ok {
+		return ""
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// skip env's flags, e.g. "#!/usr/bin/env -S deno run"
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				interpreter = f
+				break
+			}
+		}
+	}
+
+	// strip versions, e.g. python3 or python3.12
+	interpreter = strings.TrimRight(interpreter, "0123456789.")
+	return interpreters[interpreter]
+}
@@ -0,0 +1,38 @@
+package langdetect_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/langdetect"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		lang string
+	}{
+		{name: "shebang", code: "#!/bin/sh\nls -la\n", lang: "bash"},
+		{name: "env shebang", code: "#!/usr/bin/env python3\nprint('hi')\n", lang: "python"},
+		{name: "env flags", code: "#!/usr/bin/env -S deno run\nconsole.log(1)\n", lang: "typescript"},
+		{name: "json", code: "{\"a\": [1, 2]}\n", lang: "json"},
+		{name: "go", code: "package main\n\nfunc main() {\n\tx := 1\n}\n", lang: "go"},
+		{name: "python", code: "def add(a, b):\n    return a + b\n", lang: "python"},
+		{name: "rust", code: "fn main() {\n    let mut x = 1;\n    println!(\"{}\", x);\n}\n", lang: "rust"},
+		{name: "c", code: "#include <stdio.h>\n\nint main() {}\n", lang: "c"},
+		{name: "sql", code: "SELECT id, name FROM users WHERE id = 1;\n", lang: "sql"},
+		{name: "dockerfile", code: "FROM golang:1.22\nRUN go build ./...\n", lang: "docker"},
+		{name: "yaml", code: "name: glamour\nversion: 1\ntags:\n  - go\n", lang: "yaml"},
+		{name: "shell commands", code: "go install example.com/tool@latest\n", lang: "bash"},
+		{name: "prose", code: "just some words\n", lang: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lang, confidence := langdetect.Detect(tc.code)
+			if lang != tc.lang {
+				t.Errorf("expected %q, got %q (confidence %.2f)", tc.lang, lang, confidence)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package numfmt detects numbers in table cells, groups their digits in
+// thousands and lines them up on their decimal points.
+package numfmt
+
+import (
+	"regexp"
+	"strings"
+)
+
+var numberRe = regexp.MustCompile(`^[-+]?[$€£¥]?(\d{1,3}(,\d{3})+|\d+)?(\.\d+)?([eE][-+]?\d+)?%?$`)
+
+// IsNumber reports whether s looks like a number, allowing signs, thousands
+// separators, currency symbols, exponents and percentages.
+func IsNumber(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.IndexFunc(s, isDigit) < 0 {
+		return false
+	}
+	return numberRe.MatchString(s)
+}
+
+// IsNegative reports whether the number s is negative.
+func IsNegative(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), "-")
+}
+
+// split splits a number into the part before its integer digits, its
+// integer digits, and the rest.
+func split(s string) (string, string, string) {
+	start := strings.IndexFunc(s, isDigit)
+	if start < 0 {
+		start = len(s)
+	}
+	end := start
+	for end < len(s) && (isDigit(rune(s[end])) || s[end] == ',') {
+		end++
+	}
+	return s[:start], s[start:end], s[end:]
+}
+
+// Group groups the integer digits of the number s in thousands, separated by
+// sep. Numbers already grouped are left as they are.
+func Group(s, sep string) string {
+	head, digits, tail := split(s)
+	if sep == "" || strings.Contains(digits, ",") || len(digits) <= 3 {
+		return s
+	}
+
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteRune(r)
+	}
+	return head + b.String() + tail
+}
+
+// Align pads the numbers of a column on the right so their decimal points
+// line up when they're right-aligned. Empty cells are left as they are.
+func Align(numbers []string) []string {
+	var width int
+	for _, n := range numbers {
+		width = max(width, len([]rune(fraction(n))))
+	}
+
+	aligned := make([]string, len(numbers))
+	for i, n := range numbers {
+		if n == "" {
+			continue
+		}
+		aligned[i] = n + strings.Repeat(" ", width-len([]rune(fraction(n))))
+	}
+	return aligned
+}
+
+// fraction returns the part of a number following its integer digits.
+func fraction(s string) string {
+	_, _, tail := split(s)
+	return tail
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
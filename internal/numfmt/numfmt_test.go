@@ -0,0 +1,55 @@
+package numfmt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/numfmt"
+)
+
+func TestIsNumber(t *testing.T) {
+	for s, want := range map[string]bool{
+		"42":      true,
+		"-3.5":    true,
+		"1,234":   true,
+		"$9.99":   true,
+		"12%":     true,
+		"6.02e23": true,
+		".5":      true,
+		"":        false,
+		"-":       false,
+		"1.2.3":   false,
+		"12ab":    false,
+		"v1":      false,
+	} {
+		if got := numfmt.IsNumber(s); got != want {
+			t.Errorf("IsNumber(%q): expected %v, got %v", s, want, got)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		in, sep, want string
+	}{
+		{"1234567", ",", "1,234,567"},
+		{"-1234.5678", ",", "-1,234.5678"},
+		{"$12345", " ", "$12 345"},
+		{"999", ",", "999"},
+		{"1,234", " ", "1,234"},
+		{"1234", "", "1234"},
+	}
+	for _, tc := range tests {
+		if got := numfmt.Group(tc.in, tc.sep); got != tc.want {
+			t.Errorf("Group(%q, %q): expected %q, got %q", tc.in, tc.sep, tc.want, got)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	got := numfmt.Align([]string{"1.5", "22", "", "-3.25", "40%"})
+	want := []string{"1.5 ", "22   ", "", "-3.25", "40%  "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
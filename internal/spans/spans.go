@@ -0,0 +1,127 @@
+// Package spans provides a goldmark extension for inline spans delimited
+// by punctuation: ==mark==, ^sup^, ~sub~, ++ins++ and ||spoiler||.
+package spans
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Span types.
+const (
+	Mark    = "mark"
+	Sup     = "sup"
+	Sub     = "sub"
+	Ins     = "ins"
+	Spoiler = "spoiler"
+)
+
+// KindSpan is the NodeKind of a Span node.
+var KindSpan = ast.NewNodeKind("Span")
+
+// Span is an inline node holding the contents of a span.
+type Span struct {
+	ast.BaseInline
+
+	// SpanType is the span's type, e.g. "mark".
+	SpanType string
+}
+
+// Kind implements ast.Node.Kind.
+func (n *Span) Kind() ast.NodeKind {
+	return KindSpan
+}
+
+// Dump implements ast.Node.Dump.
+func (n *Span) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"SpanType": n.SpanType,
+	}, nil)
+}
+
+// NewSpan returns a new Span node.
+func NewSpan(spanType string) *Span {
+	return &Span{
+		SpanType: spanType,
+	}
+}
+
+// delimiters holds the delimiter character and length of every span type.
+var delimiters = []struct {
+	spanType string
+	char     byte
+	length   int
+}{
+	{Mark, '=', 2},
+	{Sup, '^', 1},
+	{Sub, '~', 1},
+	{Ins, '+', 2},
+	{Spoiler, '|', 2},
+}
+
+type delimiterProcessor struct {
+	spanType string
+	char     byte
+}
+
+func (p *delimiterProcessor) IsDelimiter(b byte) bool {
+	return b == p.char
+}
+
+func (p *delimiterProcessor) CanOpenCloser(opener, closer *parser.Delimiter) bool {
+	// don't pair ~ with the ~~ delimiters of strikethrough
+	return opener.Processor == closer.Processor
+}
+
+func (p *delimiterProcessor) OnMatch(int) ast.Node {
+	return NewSpan(p.spanType)
+}
+
+type spanParser struct {
+	processor *delimiterProcessor
+	length    int
+}
+
+func (s *spanParser) Trigger() []byte {
+	return []byte{s.processor.char}
+}
+
+// Parse parses a delimiter run of exactly the span type's length, leaving
+// other runs, e.g. ~~ strikethrough, to other parsers.
+func (s *spanParser) Parse(_ ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	before := block.PrecendingCharacter()
+	line, segment := block.PeekLine()
+	node := parser.ScanDelimiter(line, before, 1, s.processor)
+	if node == nil || node.OriginalLength != s.length || before == rune(s.processor.char) {
+		return nil
+	}
+
+	node.Segment = segment.WithStop(segment.Start + node.OriginalLength)
+	block.Advance(node.OriginalLength)
+	pc.PushDelimiter(node)
+	return node
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that parses ==mark==, ^sup^, ~sub~,
+// ++ins++ and ||spoiler|| spans into Span nodes. Single tildes no longer
+// mark strikethrough text.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	parsers := make([]util.PrioritizedValue, 0, len(delimiters))
+	for _, d := range delimiters {
+		parsers = append(parsers, util.Prioritized(&spanParser{
+			processor: &delimiterProcessor{spanType: d.spanType, char: d.char},
+			length:    d.length,
+		}, 400)) //nolint: mnd
+	}
+	m.Parser().AddOptions(parser.WithInlineParsers(parsers...))
+}
@@ -0,0 +1,58 @@
+package spans_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/spans"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"a ==b== c", "Text mark[Text] Text"},
+		{"x^2^", "Text sup[Text]"},
+		{"H~2~O", "Text sub[Text] Text"},
+		{"~~gone~~", "Strikethrough[Text]"},
+		{"++new++ and ||secret||", "ins[Text] Text spoiler[Text]"},
+		{"==*nested*==", "mark[Emphasis[Text]]"},
+		{"a === b", "Text"},
+		{"==open", "Text"},
+		{"a ^b~ c", "Text"},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM, spans.New()))
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tt.in)))
+			if got := dump(doc.FirstChild()); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// dump describes the children of n, merging adjacent text nodes.
+func dump(n ast.Node) string {
+	var parts []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		s := c.Kind().String()
+		if span, ok := c.(*spans.Span); ok {
+			s = span.SpanType
+		}
+		if c.HasChildren() {
+			s += "[" + dump(c) + "]"
+		}
+		if s == "Text" && len(parts) > 0 && parts[len(parts)-1] == "Text" {
+			continue
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,490 @@
+package texmath
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// box is a block of text lines with a baseline, used to lay out formulas
+// on a character grid.
+type box struct {
+	lines []string
+	base  int
+}
+
+func textBox(s string) box {
+	return box{lines: []string{s}}
+}
+
+func (b box) width() int {
+	w := 0
+	for _, l := range b.lines {
+		w = max(w, ansi.StringWidth(l))
+	}
+	return w
+}
+
+func pad(s string, w int) string {
+	return s + strings.Repeat(" ", max(w-ansi.StringWidth(s), 0))
+}
+
+func center(s string, w int) string {
+	left := max(w-ansi.StringWidth(s), 0) / 2 //nolint: mnd
+	return pad(strings.Repeat(" ", left)+s, w)
+}
+
+// hcat joins boxes horizontally, aligning their baselines.
+func hcat(boxes ...box) box {
+	ascent, descent := 0, 0
+	for _, b := range boxes {
+		ascent = max(ascent, b.base)
+		descent = max(descent, len(b.lines)-1-b.base)
+	}
+
+	lines := make([]string, ascent+descent+1)
+	for _, b := range boxes {
+		w := b.width()
+		for i := range lines {
+			s := ""
+			if j := i - (ascent - b.base); j >= 0 && j < len(b.lines) {
+				s = b.lines[j]
+			}
+			lines[i] += pad(s, w)
+		}
+	}
+	return box{lines: lines, base: ascent}
+}
+
+// vcat stacks boxes vertically, centering them. The baseline is set to the
+// baseline of the box at index base.
+func vcat(base int, boxes ...box) box {
+	w := 0
+	for _, b := range boxes {
+		w = max(w, b.width())
+	}
+
+	var out box
+	for i, b := range boxes {
+		if i == base {
+			out.base = len(out.lines) + b.base
+		}
+		for _, l := range b.lines {
+			out.lines = append(out.lines, center(l, w))
+		}
+	}
+	return out
+}
+
+// layout renders parsed formulas, either inline on a single line or as
+// display math spanning multiple lines.
+type layout struct {
+	display bool
+
+	// script lays out sub- and superscripts, which are set without spaces
+	// around operators.
+	scriptStyle bool
+}
+
+func (l layout) render(n node) box {
+	switch n := n.(type) {
+	case *symbolNode:
+		return textBox(n.text)
+	case *groupNode:
+		return l.list(n.items)
+	case *scriptNode:
+		return l.script(n)
+	case *fracNode:
+		return l.frac(n)
+	case *sqrtNode:
+		return l.sqrt(n)
+	case *accentNode:
+		return l.accent(n)
+	case *delimNode:
+		b := l.render(n.body)
+		return hcat(delimiter(n.left, b), b, delimiter(n.right, b))
+	case *gridNode:
+		return l.grid(n)
+	}
+	return textBox("")
+}
+
+// flat renders n on a single line.
+func flat(n node) string {
+	return strings.Join(layout{}.render(n).lines, " ")
+}
+
+// flatScript renders a sub- or superscript on a single line.
+func flatScript(n node) string {
+	return strings.Join(layout{scriptStyle: true}.render(n).lines, " ")
+}
+
+func kindOf(n node) atomKind {
+	switch n := n.(type) {
+	case *symbolNode:
+		return n.kind
+	case *scriptNode:
+		return kindOf(n.base)
+	}
+	return ord
+}
+
+// list lays out a list of atoms, spacing operators and relations the way TeX
+// does.
+func (l layout) list(items []node) box {
+	boxes := make([]box, 0, len(items)*2) //nolint: mnd
+	prev := -1
+	for _, item := range items {
+		kind := kindOf(item)
+		if kind == bin && (prev == -1 || prev == int(bin) || prev == int(rel) || prev == int(open) || prev == int(punct) || prev == int(op) || prev == int(bigop)) {
+			// unary operator
+			kind = ord
+		}
+		if prev != -1 && !l.scriptStyle && spaced(atomKind(prev), kind) {
+			boxes = append(boxes, textBox(" "))
+		}
+		boxes = append(boxes, l.render(item))
+		prev = int(kind)
+	}
+	if len(boxes) == 0 {
+		return textBox("")
+	}
+	return hcat(boxes...)
+}
+
+// spaced reports whether a space goes between atoms of kind a and b.
+func spaced(a, b atomKind) bool {
+	switch {
+	case a == space || b == space:
+		return false
+	case a == bin || a == rel || b == bin || b == rel:
+		return true
+	case a == punct:
+		return true
+	case a == op || a == bigop:
+		return b == ord || b == op || b == bigop
+	case b == op || b == bigop:
+		return a == ord || a == closing
+	}
+	return false
+}
+
+func (l layout) script(n *scriptNode) box {
+	base := l.render(n.base)
+	scripts := layout{display: l.display, scriptStyle: true}
+
+	if s, ok := n.base.(*symbolNode); ok && l.display && limits[s.text] {
+		var boxes []box
+		if n.sup != nil {
+			boxes = append(boxes, scripts.render(n.sup))
+		}
+		boxes = append(boxes, base)
+		if n.sub != nil {
+			boxes = append(boxes, scripts.render(n.sub))
+		}
+		idx := 0
+		if n.sup != nil {
+			idx = 1
+		}
+		return vcat(idx, boxes...)
+	}
+
+	sub, sup := "", ""
+	subOK, supOK := true, true
+	if n.sub != nil {
+		sub, subOK = mapRunes(flatScript(n.sub), subscripts)
+	}
+	if n.sup != nil {
+		sup, supOK = mapRunes(flatScript(n.sup), superscripts)
+	}
+	if subOK && supOK {
+		return hcat(base, textBox(sub+sup))
+	}
+
+	if !l.display {
+		s := ""
+		if n.sub != nil {
+			s += "_" + scriptParens(flatScript(n.sub))
+		}
+		if n.sup != nil {
+			s += "^" + scriptParens(flatScript(n.sup))
+		}
+		return hcat(base, textBox(s))
+	}
+
+	// raise and lower the scripts next to the base
+	var col box
+	if n.sup != nil {
+		col.lines = append(col.lines, scripts.render(n.sup).lines...)
+	}
+	col.base = len(col.lines)
+	col.lines = append(col.lines, "")
+	if n.sub != nil {
+		col.lines = append(col.lines, scripts.render(n.sub).lines...)
+	}
+	return hcat(base, col)
+}
+
+// scriptParens wraps a script in parentheses unless it is a single rune.
+func scriptParens(s string) string {
+	if len([]rune(s)) > 1 && !(strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")) {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// parens wraps s in parentheses if it contains operators.
+func parens(s string) string {
+	if strings.ContainsAny(s, " +−-=,/") {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func (l layout) frac(n *fracNode) box {
+	if !l.display {
+		num, den := flat(n.num), flat(n.den)
+		if n.binom {
+			return textBox("C(" + num + ", " + den + ")")
+		}
+		if v, ok := vulgarFractions[num+"/"+den]; ok {
+			return textBox(v)
+		}
+		return textBox(parens(num) + "/" + parens(den))
+	}
+
+	num, den := l.render(n.num), l.render(n.den)
+	w := max(num.width(), den.width()) + 2 //nolint: mnd
+	bar := strings.Repeat("─", w)
+	if n.binom {
+		bar = strings.Repeat(" ", w)
+	}
+	b := vcat(1, num, textBox(bar), den)
+	if n.binom {
+		return hcat(delimiter("(", b), b, delimiter(")", b))
+	}
+	return b
+}
+
+func (l layout) sqrt(n *sqrtNode) box {
+	index := ""
+	if n.index != nil {
+		index = flat(n.index)
+	}
+
+	if !l.display {
+		body := flat(n.body)
+		if len([]rune(body)) > 1 {
+			body = "(" + body + ")"
+		}
+		switch index {
+		case "":
+			return textBox("√" + body)
+		case "3":
+			return textBox("∛" + body)
+		case "4":
+			return textBox("∜" + body)
+		}
+		if sup, ok := mapRunes(index, superscripts); ok {
+			return textBox(sup + "√" + body)
+		}
+		return textBox("root" + parens(index) + body)
+	}
+
+	body := l.render(n.body)
+	out := box{
+		lines: []string{" " + strings.Repeat("_", body.width())},
+		base:  body.base + 1,
+	}
+	for i, line := range body.lines {
+		sign := "│"
+		if i == len(body.lines)-1 {
+			sign = "√"
+		}
+		out.lines = append(out.lines, sign+line)
+	}
+	if index != "" {
+		if sup, ok := mapRunes(index, superscripts); ok {
+			index = sup
+		}
+		out = hcat(box{lines: []string{index}}, out)
+	}
+	return out
+}
+
+func (l layout) accent(n *accentNode) box {
+	b := l.render(n.body)
+	if len(b.lines) > 1 {
+		return b
+	}
+	runes := []rune(b.lines[0])
+	if len(runes) == 1 {
+		return textBox(string(runes[0]) + string(n.mark))
+	}
+
+	var sb strings.Builder
+	for i, r := range runes {
+		sb.WriteRune(r)
+		if n.mark == '̅' || n.mark == '̲' || i == len(runes)-1 {
+			sb.WriteRune(n.mark)
+		}
+	}
+	return textBox(sb.String())
+}
+
+var environments = map[string][2]string{
+	"pmatrix": {"(", ")"},
+	"bmatrix": {"[", "]"},
+	"Bmatrix": {"{", "}"},
+	"vmatrix": {"|", "|"},
+	"Vmatrix": {"‖", "‖"},
+	"cases":   {"{", ""},
+}
+
+// inlineEnvironments maps environments to their delimiters on a single line.
+var inlineEnvironments = map[string][2]string{
+	"cases": {"{", "}"},
+}
+
+func (l layout) grid(n *gridNode) box {
+	delims := environments[n.env]
+
+	if !l.display {
+		if d, ok := inlineEnvironments[n.env]; ok {
+			delims = d
+		}
+		sep := " "
+		if n.env == "cases" {
+			sep = ", "
+		}
+		rows := make([]string, 0, len(n.rows))
+		for _, row := range n.rows {
+			cells := make([]string, 0, len(row))
+			for _, cell := range row {
+				if s := flat(cell); s != "" {
+					cells = append(cells, s)
+				}
+			}
+			rows = append(rows, strings.Join(cells, sep))
+		}
+		return textBox(delims[0] + strings.Join(rows, "; ") + delims[1])
+	}
+
+	cells := make([][]box, len(n.rows))
+	var widths []int
+	for i, row := range n.rows {
+		for j, cell := range row {
+			b := l.render(cell)
+			cells[i] = append(cells[i], b)
+			if j >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], b.width())
+		}
+	}
+
+	var out box
+	for _, row := range cells {
+		boxes := make([]box, 0, len(row)*2) //nolint: mnd
+		for j := range widths {
+			b := textBox("")
+			if j < len(row) {
+				b = row[j]
+			}
+			switch {
+			case j > 0 && n.env == "aligned" && j%2 == 1:
+				boxes = append(boxes, textBox(" "))
+			case j > 0:
+				boxes = append(boxes, textBox("  "))
+			}
+			boxes = append(boxes, alignCell(b, widths[j], n.env, j))
+		}
+		out.lines = append(out.lines, hcat(boxes...).lines...)
+	}
+	out.base = (len(out.lines) - 1) / 2 //nolint: mnd
+
+	if delims[0] == "" && delims[1] == "" {
+		return out
+	}
+	return hcat(delimiter(delims[0], out), out, delimiter(delims[1], out))
+}
+
+// alignCell pads a cell to the column width. Matrices are centered, cases
+// left-aligned and aligned environments alternate between right- and
+// left-aligned columns.
+func alignCell(b box, w int, env string, col int) box {
+	for i, line := range b.lines {
+		switch {
+		case env == "cases" || (env == "aligned" && col%2 == 1):
+			b.lines[i] = pad(line, w)
+		case env == "aligned":
+			b.lines[i] = strings.Repeat(" ", max(w-b.width(), 0)) + pad(line, b.width())
+		default:
+			b.lines[i] = strings.Repeat(" ", max(w-b.width(), 0)/2) + pad(line, b.width()) //nolint: mnd
+			b.lines[i] = pad(b.lines[i], w)
+		}
+	}
+	return b
+}
+
+// delimiters maps a delimiter to its top, middle, extension and bottom
+// pieces when it spans multiple lines.
+var delimiters = map[string][4]string{
+	"(": {"⎛", "⎜", "⎜", "⎝"},
+	")": {"⎞", "⎟", "⎟", "⎠"},
+	"[": {"⎡", "⎢", "⎢", "⎣"},
+	"]": {"⎤", "⎥", "⎥", "⎦"},
+	"{": {"⎧", "⎨", "⎪", "⎩"},
+	"}": {"⎫", "⎬", "⎪", "⎭"},
+	"|": {"│", "│", "│", "│"},
+	"‖": {"‖", "‖", "‖", "‖"},
+}
+
+// delimiter returns a delimiter as tall as b.
+func delimiter(d string, b box) box {
+	h := len(b.lines)
+	if d == "" {
+		return box{lines: make([]string, h), base: b.base}
+	}
+	if h == 1 {
+		return box{lines: []string{d}, base: b.base}
+	}
+
+	pieces, ok := delimiters[d]
+	if !ok {
+		pieces = [4]string{" ", d, " ", " "}
+	}
+	lines := make([]string, h)
+	for i := range lines {
+		switch {
+		case i == 0:
+			lines[i] = pieces[0]
+		case i == h-1:
+			lines[i] = pieces[3]
+		case i == h/2: //nolint: mnd
+			lines[i] = pieces[1]
+		default:
+			lines[i] = pieces[2]
+		}
+	}
+	if !ok {
+		lines[h/2] = d //nolint: mnd
+	}
+	return box{lines: lines, base: b.base}
+}
+
+// Inline converts a TeX formula to a single line of Unicode text.
+func Inline(tex string) string {
+	return flat(parse(tex))
+}
+
+// Display converts a TeX formula to Unicode text laid out on a character
+// grid, stacking fractions, limits and matrices across multiple lines.
+func Display(tex string) []string {
+	b := layout{display: true}.render(parse(tex))
+	lines := make([]string, len(b.lines))
+	for i, line := range b.lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
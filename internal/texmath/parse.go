@@ -0,0 +1,359 @@
+package texmath
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// node is an element of a parsed TeX formula.
+type node interface{}
+
+type symbolNode struct {
+	text string
+	kind atomKind
+}
+
+type groupNode struct {
+	items []node
+}
+
+type scriptNode struct {
+	base     node
+	sub, sup node
+}
+
+type fracNode struct {
+	num, den node
+	binom    bool
+}
+
+type sqrtNode struct {
+	index, body node
+}
+
+type accentNode struct {
+	mark rune
+	body node
+}
+
+type delimNode struct {
+	left, right string
+	body        node
+}
+
+type gridNode struct {
+	env  string
+	rows [][]node
+}
+
+// parse parses a TeX formula. Top-level rows separated by \\ and columns
+// separated by & are parsed as an aligned grid.
+func parse(tex string) node {
+	p := &texParser{s: tex}
+	g := p.grid("aligned")
+	for p.pos < len(p.s) {
+		// skip a stray closing brace or \end
+		p.pos++
+		g.rows = append(g.rows, p.grid("aligned").rows...)
+	}
+	if len(g.rows) == 1 && len(g.rows[0]) == 1 {
+		return g.rows[0][0]
+	}
+	return g
+}
+
+type texParser struct {
+	s   string
+	pos int
+}
+
+func (p *texParser) peek(prefix string) bool {
+	return strings.HasPrefix(p.s[p.pos:], prefix)
+}
+
+func (p *texParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// list parses atoms until the end of input, a closing brace or any of the
+// given stop prefixes.
+func (p *texParser) list(stops ...string) []node {
+	var items []node
+	for p.pos < len(p.s) {
+		if p.s[p.pos] == '}' {
+			return items
+		}
+		for _, stop := range stops {
+			if p.peek(stop) {
+				return items
+			}
+		}
+
+		c := p.s[p.pos]
+		if c != '^' && c != '_' {
+			if n := p.atom(); n != nil {
+				items = append(items, n)
+			}
+			continue
+		}
+
+		p.pos++
+		arg := p.arg()
+		var s *scriptNode
+		if len(items) > 0 {
+			if sn, ok := items[len(items)-1].(*scriptNode); ok && ((c == '^' && sn.sup == nil) || (c == '_' && sn.sub == nil)) {
+				s = sn
+			} else {
+				s = &scriptNode{base: items[len(items)-1]}
+				items[len(items)-1] = s
+			}
+		} else {
+			s = &scriptNode{base: &symbolNode{}}
+			items = append(items, s)
+		}
+		if c == '^' {
+			s.sup = arg
+		} else {
+			s.sub = arg
+		}
+	}
+	return items
+}
+
+// arg parses a command argument: either a braced group or a single atom.
+func (p *texParser) arg() node {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return &groupNode{}
+	}
+	if p.s[p.pos] == '{' {
+		return p.group()
+	}
+	if n := p.atom(); n != nil {
+		return n
+	}
+	return &groupNode{}
+}
+
+func (p *texParser) group() node {
+	p.pos++ // {
+	items := p.list()
+	if p.pos < len(p.s) {
+		p.pos++ // }
+	}
+	return &groupNode{items: items}
+}
+
+// raw returns the verbatim content of a braced argument.
+func (p *texParser) raw() string {
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '{' {
+		return ""
+	}
+	depth, start := 0, p.pos+1
+	for ; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return p.s[start : p.pos-1]
+			}
+		}
+	}
+	return p.s[start:]
+}
+
+func (p *texParser) atom() node {
+	c := p.s[p.pos]
+	switch c {
+	case ' ', '\t', '\r', '\n', '&':
+		p.pos++
+		return nil
+	case '{':
+		return p.group()
+	case '\\':
+		return p.command()
+	}
+
+	r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+	p.pos += size
+	return char(r)
+}
+
+func char(r rune) node {
+	switch r {
+	case '+':
+		return &symbolNode{"+", bin}
+	case '-':
+		return &symbolNode{"−", bin}
+	case '*':
+		return &symbolNode{"∗", bin}
+	case '=', '<', '>', ':':
+		return &symbolNode{string(r), rel}
+	case ',', ';':
+		return &symbolNode{string(r), punct}
+	case '(', '[':
+		return &symbolNode{string(r), open}
+	case ')', ']':
+		return &symbolNode{string(r), closing}
+	case '\'':
+		return &symbolNode{"′", ord}
+	case '~':
+		return &symbolNode{" ", space}
+	}
+	return &symbolNode{string(r), ord}
+}
+
+func (p *texParser) command() node {
+	p.pos++ // backslash
+	if p.pos >= len(p.s) {
+		return &symbolNode{`\`, ord}
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && isLetter(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		// control symbol
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		p.pos += size
+		switch r {
+		case ',', ':', ';', ' ':
+			return &symbolNode{" ", space}
+		case '!', '\\':
+			return nil
+		case '|':
+			return &symbolNode{"‖", ord}
+		case '{':
+			return &symbolNode{"{", open}
+		case '}':
+			return &symbolNode{"}", closing}
+		}
+		return &symbolNode{string(r), ord}
+	}
+
+	name := p.s[start:p.pos]
+	switch name {
+	case "frac", "dfrac", "tfrac", "cfrac":
+		return &fracNode{num: p.arg(), den: p.arg()}
+	case "binom", "dbinom", "tbinom":
+		return &fracNode{num: p.arg(), den: p.arg(), binom: true}
+	case "sqrt":
+		var index node
+		p.skipSpace()
+		if p.peek("[") {
+			p.pos++
+			end := strings.IndexByte(p.s[p.pos:], ']')
+			if end < 0 {
+				end = len(p.s) - p.pos
+			}
+			index = parse(p.s[p.pos : p.pos+end])
+			p.pos = min(p.pos+end+1, len(p.s))
+		}
+		return &sqrtNode{index: index, body: p.arg()}
+	case "text", "textrm", "textbf", "textit", "textsf", "texttt", "mbox":
+		return &symbolNode{p.raw(), ord}
+	case "operatorname":
+		return &symbolNode{p.raw(), op}
+	case "mathbb":
+		return &symbolNode{mathbb(p.raw()), ord}
+	case "mathrm", "mathbf", "mathit", "mathsf", "mathtt", "mathcal", "mathscr", "mathfrak", "boldsymbol":
+		return p.arg()
+	case "left":
+		left := p.delim()
+		items := p.list(`\right`)
+		right := ""
+		if p.peek(`\right`) {
+			p.pos += len(`\right`)
+			right = p.delim()
+		}
+		return &delimNode{left: left, right: right, body: &groupNode{items: items}}
+	case "right":
+		// unbalanced \right
+		p.delim()
+		return nil
+	case "big", "Big", "bigg", "Bigg", "bigl", "bigr", "Bigl", "Bigr", "biggl", "biggr", "Biggl", "Biggr":
+		return nil
+	case "displaystyle", "textstyle", "limits", "nolimits":
+		return nil
+	case "begin":
+		env := p.raw()
+		g := p.grid(env)
+		if p.peek(`\end`) {
+			p.pos += len(`\end`)
+			p.raw()
+		}
+		return g
+	case "end":
+		p.raw()
+		return nil
+	case "not":
+		n := p.arg()
+		if s, ok := n.(*symbolNode); ok {
+			return &symbolNode{s.text + "̸", s.kind}
+		}
+		return n
+	}
+
+	if mark, ok := accents[name]; ok {
+		return &accentNode{mark: mark, body: p.arg()}
+	}
+	if s, ok := symbols[name]; ok {
+		return &symbolNode{s.text, s.kind}
+	}
+	if functions[name] {
+		return &symbolNode{name, op}
+	}
+	return &symbolNode{`\` + name, ord}
+}
+
+// delim parses the delimiter following \left or \right. A period denotes
+// an empty delimiter.
+func (p *texParser) delim() string {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return ""
+	}
+	if p.s[p.pos] == '.' {
+		p.pos++
+		return ""
+	}
+	if s, ok := p.atom().(*symbolNode); ok {
+		return s.text
+	}
+	return ""
+}
+
+// grid parses the rows and cells of an environment up to its \end.
+func (p *texParser) grid(env string) *gridNode {
+	g := &gridNode{env: env}
+	var row []node
+	for {
+		row = append(row, &groupNode{items: p.list("&", `\\`, `\end`)})
+		switch {
+		case p.peek("&"):
+			p.pos++
+			continue
+		case p.peek(`\\`):
+			p.pos += 2
+			g.rows = append(g.rows, row)
+			row = nil
+			continue
+		}
+		break
+	}
+	if len(row) > 1 || len(row[0].(*groupNode).items) > 0 || len(g.rows) == 0 {
+		g.rows = append(g.rows, row)
+	}
+	return g
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
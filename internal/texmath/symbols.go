@@ -0,0 +1,204 @@
+package texmath
+
+// atomKind classifies atoms for spacing, following TeX's atom types.
+type atomKind int
+
+const (
+	ord atomKind = iota
+	op
+	bigop
+	bin
+	rel
+	punct
+	open
+	closing
+	space
+)
+
+type symbol struct {
+	text string
+	kind atomKind
+}
+
+var symbols = map[string]symbol{
+	// lowercase greek
+	"alpha": {"α", ord}, "beta": {"β", ord}, "gamma": {"γ", ord},
+	"delta": {"δ", ord}, "epsilon": {"ϵ", ord}, "varepsilon": {"ε", ord},
+	"zeta": {"ζ", ord}, "eta": {"η", ord}, "theta": {"θ", ord},
+	"vartheta": {"ϑ", ord}, "iota": {"ι", ord}, "kappa": {"κ", ord},
+	"lambda": {"λ", ord}, "mu": {"μ", ord}, "nu": {"ν", ord},
+	"xi": {"ξ", ord}, "pi": {"π", ord}, "varpi": {"ϖ", ord},
+	"rho": {"ρ", ord}, "varrho": {"ϱ", ord}, "sigma": {"σ", ord},
+	"varsigma": {"ς", ord}, "tau": {"τ", ord}, "upsilon": {"υ", ord},
+	"phi": {"ϕ", ord}, "varphi": {"φ", ord}, "chi": {"χ", ord},
+	"psi": {"ψ", ord}, "omega": {"ω", ord},
+
+	// uppercase greek
+	"Gamma": {"Γ", ord}, "Delta": {"Δ", ord}, "Theta": {"Θ", ord},
+	"Lambda": {"Λ", ord}, "Xi": {"Ξ", ord}, "Pi": {"Π", ord},
+	"Sigma": {"Σ", ord}, "Upsilon": {"Υ", ord}, "Phi": {"Φ", ord},
+	"Psi": {"Ψ", ord}, "Omega": {"Ω", ord},
+
+	// binary operators
+	"pm": {"±", bin}, "mp": {"∓", bin}, "times": {"×", bin},
+	"div": {"÷", bin}, "cdot": {"·", bin}, "ast": {"∗", bin},
+	"star": {"⋆", bin}, "circ": {"∘", bin}, "bullet": {"•", bin},
+	"oplus": {"⊕", bin}, "otimes": {"⊗", bin}, "cup": {"∪", bin},
+	"cap": {"∩", bin}, "setminus": {"∖", bin}, "wedge": {"∧", bin},
+	"land": {"∧", bin}, "vee": {"∨", bin}, "lor": {"∨", bin},
+
+	// relations
+	"leq": {"≤", rel}, "le": {"≤", rel}, "geq": {"≥", rel},
+	"ge": {"≥", rel}, "neq": {"≠", rel}, "ne": {"≠", rel},
+	"approx": {"≈", rel}, "equiv": {"≡", rel}, "sim": {"∼", rel},
+	"simeq": {"≃", rel}, "cong": {"≅", rel}, "propto": {"∝", rel},
+	"ll": {"≪", rel}, "gg": {"≫", rel}, "in": {"∈", rel},
+	"notin": {"∉", rel}, "ni": {"∋", rel}, "subset": {"⊂", rel},
+	"subseteq": {"⊆", rel}, "supset": {"⊃", rel}, "supseteq": {"⊇", rel},
+	"to": {"→", rel}, "rightarrow": {"→", rel}, "leftarrow": {"←", rel},
+	"gets": {"←", rel}, "leftrightarrow": {"↔", rel}, "Rightarrow": {"⇒", rel},
+	"implies": {"⟹", rel}, "Leftarrow": {"⇐", rel}, "Leftrightarrow": {"⇔", rel},
+	"iff": {"⟺", rel}, "mapsto": {"↦", rel}, "mid": {"∣", rel},
+	"parallel": {"∥", rel}, "perp": {"⊥", rel}, "models": {"⊨", rel},
+	"vdash": {"⊢", rel}, "coloneqq": {"≔", rel},
+
+	// ordinary symbols
+	"infty": {"∞", ord}, "partial": {"∂", ord}, "nabla": {"∇", ord},
+	"forall": {"∀", ord}, "exists": {"∃", ord}, "nexists": {"∄", ord},
+	"emptyset": {"∅", ord}, "varnothing": {"∅", ord}, "neg": {"¬", ord},
+	"lnot": {"¬", ord}, "angle": {"∠", ord}, "triangle": {"△", ord},
+	"hbar": {"ℏ", ord}, "ell": {"ℓ", ord}, "Re": {"ℜ", ord},
+	"Im": {"ℑ", ord}, "aleph": {"ℵ", ord}, "prime": {"′", ord},
+	"ldots": {"…", ord}, "dots": {"…", ord}, "cdots": {"⋯", ord},
+	"vdots": {"⋮", ord}, "ddots": {"⋱", ord}, "degree": {"°", ord},
+
+	// delimiters
+	"langle": {"⟨", open}, "rangle": {"⟩", closing},
+	"lfloor": {"⌊", open}, "rfloor": {"⌋", closing},
+	"lceil": {"⌈", open}, "rceil": {"⌉", closing},
+	"lvert": {"|", open}, "rvert": {"|", closing},
+	"lVert": {"‖", open}, "rVert": {"‖", closing},
+
+	// large operators
+	"sum": {"∑", bigop}, "prod": {"∏", bigop}, "coprod": {"∐", bigop},
+	"int": {"∫", bigop}, "iint": {"∬", bigop}, "iiint": {"∭", bigop},
+	"oint": {"∮", bigop}, "bigcup": {"⋃", bigop}, "bigcap": {"⋂", bigop},
+	"bigoplus": {"⨁", bigop}, "bigotimes": {"⨂", bigop},
+
+	// spacing
+	"quad": {"  ", space}, "qquad": {"    ", space},
+}
+
+// functions are rendered upright as operator names.
+var functions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true,
+	"csc": true, "arcsin": true, "arccos": true, "arctan": true,
+	"sinh": true, "cosh": true, "tanh": true, "log": true, "ln": true,
+	"lg": true, "exp": true, "det": true, "dim": true, "ker": true,
+	"deg": true, "gcd": true, "arg": true, "Pr": true, "mod": true,
+	"bmod": true,
+	// these take their subscripts as limits in display math
+	"lim": true, "liminf": true, "limsup": true, "max": true, "min": true,
+	"sup": true, "inf": true,
+}
+
+// limits lists the operators whose scripts are set above and below them in
+// display math.
+var limits = map[string]bool{
+	"∑": true, "∏": true, "∐": true, "⋃": true, "⋂": true, "⨁": true, "⨂": true,
+	"lim": true, "liminf": true, "limsup": true, "max": true, "min": true,
+	"sup": true, "inf": true,
+}
+
+var accents = map[string]rune{
+	"hat":       '̂',
+	"widehat":   '̂',
+	"tilde":     '̃',
+	"widetilde": '̃',
+	"bar":       '̄',
+	"overline":  '̅',
+	"dot":       '̇',
+	"ddot":      '̈',
+	"vec":       '⃗',
+	"underline": '̲',
+}
+
+var superscripts = map[rune]rune{
+	'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴',
+	'5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+	'+': '⁺', '-': '⁻', '−': '⁻', '=': '⁼', '(': '⁽', ')': '⁾',
+	'a': 'ᵃ', 'b': 'ᵇ', 'c': 'ᶜ', 'd': 'ᵈ', 'e': 'ᵉ', 'f': 'ᶠ',
+	'g': 'ᵍ', 'h': 'ʰ', 'i': 'ⁱ', 'j': 'ʲ', 'k': 'ᵏ', 'l': 'ˡ',
+	'm': 'ᵐ', 'n': 'ⁿ', 'o': 'ᵒ', 'p': 'ᵖ', 'r': 'ʳ', 's': 'ˢ',
+	't': 'ᵗ', 'u': 'ᵘ', 'v': 'ᵛ', 'w': 'ʷ', 'x': 'ˣ', 'y': 'ʸ',
+	'z': 'ᶻ', 'A': 'ᴬ', 'B': 'ᴮ', 'D': 'ᴰ', 'E': 'ᴱ', 'G': 'ᴳ',
+	'H': 'ᴴ', 'I': 'ᴵ', 'J': 'ᴶ', 'K': 'ᴷ', 'L': 'ᴸ', 'M': 'ᴹ',
+	'N': 'ᴺ', 'O': 'ᴼ', 'P': 'ᴾ', 'R': 'ᴿ', 'T': 'ᵀ', 'U': 'ᵁ',
+	'V': 'ⱽ', 'W': 'ᵂ', 'α': 'ᵅ', 'β': 'ᵝ', 'γ': 'ᵞ', 'δ': 'ᵟ',
+	'θ': 'ᶿ', 'φ': 'ᵠ', 'χ': 'ᵡ', '′': '′',
+}
+
+var subscripts = map[rune]rune{
+	'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄',
+	'5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+	'+': '₊', '-': '₋', '−': '₋', '=': '₌', '(': '₍', ')': '₎',
+	'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'i': 'ᵢ', 'j': 'ⱼ', 'k': 'ₖ',
+	'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ', 'o': 'ₒ', 'p': 'ₚ', 'r': 'ᵣ',
+	's': 'ₛ', 't': 'ₜ', 'u': 'ᵤ', 'v': 'ᵥ', 'x': 'ₓ', 'β': 'ᵦ',
+	'γ': 'ᵧ', 'ρ': 'ᵨ', 'φ': 'ᵩ', 'χ': 'ᵪ',
+}
+
+// doubleStruck holds the letters of \mathbb that live outside the
+// Mathematical Alphanumeric Symbols block.
+var doubleStruck = map[rune]rune{
+	'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ',
+}
+
+var vulgarFractions = map[string]string{
+	"1/2": "½", "1/3": "⅓", "2/3": "⅔", "1/4": "¼", "3/4": "¾",
+	"1/5": "⅕", "1/6": "⅙", "1/8": "⅛",
+}
+
+// Superscript converts s to Unicode superscript characters. It reports false
+// if some character has no superscript form.
+func Superscript(s string) (string, bool) {
+	return mapRunes(s, superscripts)
+}
+
+// Subscript converts s to Unicode subscript characters. It reports false if
+// some character has no subscript form.
+func Subscript(s string) (string, bool) {
+	return mapRunes(s, subscripts)
+}
+
+// mapRunes maps every rune of s using m. It reports false if any rune has
+// no mapping.
+func mapRunes(s string, m map[rune]rune) (string, bool) {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		mr, ok := m[r]
+		if !ok {
+			return "", false
+		}
+		out = append(out, mr)
+	}
+	return string(out), true
+}
+
+func mathbb(s string) string {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch {
+		case doubleStruck[r] != 0:
+			r = doubleStruck[r]
+		case r >= 'A' && r <= 'Z':
+			r = 0x1D538 + (r - 'A')
+		case r >= 'a' && r <= 'z':
+			r = 0x1D552 + (r - 'a')
+		case r >= '0' && r <= '9':
+			r = 0x1D7D8 + (r - '0')
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
@@ -0,0 +1,190 @@
+// Package texmath provides a goldmark extension that parses inline ($...$)
+// and display ($$...$$) math, and converts a practical subset of TeX to
+// Unicode text.
+package texmath
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindInlineMath is the NodeKind of an InlineMath node.
+var KindInlineMath = ast.NewNodeKind("InlineMath")
+
+// InlineMath is an inline node holding a TeX formula.
+type InlineMath struct {
+	ast.BaseInline
+
+	Formula string
+}
+
+// Kind implements ast.Node.Kind.
+func (n *InlineMath) Kind() ast.NodeKind {
+	return KindInlineMath
+}
+
+// Dump implements ast.Node.Dump.
+func (n *InlineMath) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Formula": n.Formula,
+	}, nil)
+}
+
+// KindDisplayMath is the NodeKind of a DisplayMath node.
+var KindDisplayMath = ast.NewNodeKind("DisplayMath")
+
+// DisplayMath is a block node holding a TeX formula. The formula is stored in
+// the node's lines.
+type DisplayMath struct {
+	ast.BaseBlock
+
+	// closed is set for formulas ending on their opening line.
+	closed bool
+}
+
+// Kind implements ast.Node.Kind.
+func (n *DisplayMath) Kind() ast.NodeKind {
+	return KindDisplayMath
+}
+
+// IsRaw implements ast.Node.IsRaw.
+func (n *DisplayMath) IsRaw() bool {
+	return true
+}
+
+// Dump implements ast.Node.Dump.
+func (n *DisplayMath) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// Formula returns the node's TeX formula.
+func (n *DisplayMath) Formula(source []byte) string {
+	var b bytes.Buffer
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		b.Write(line.Value(source))
+	}
+	return string(bytes.TrimSpace(b.Bytes()))
+}
+
+var delim = []byte("$$")
+
+type inlineParser struct{}
+
+func (p *inlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse parses $...$ and $$...$$ spans. To avoid mistaking prices for math,
+// a single dollar sign only opens math if it's followed by a non-space, and
+// only closes it if it's preceded by a non-space and not followed by a digit.
+func (p *inlineParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if bytes.HasPrefix(line, delim) {
+		end := bytes.Index(line[2:], delim)
+		if end <= 0 {
+			return nil
+		}
+		block.Advance(end + 4) //nolint: mnd
+		return &InlineMath{Formula: string(bytes.TrimSpace(line[2 : end+2]))}
+	}
+
+	if len(line) < 2 || util.IsSpace(line[1]) { //nolint: mnd
+		return nil
+	}
+	for i := 2; i < len(line); i++ {
+		switch {
+		case line[i] == '\\':
+			i++
+		case line[i] == '$':
+			if util.IsSpace(line[i-1]) || (i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9') {
+				continue
+			}
+			block.Advance(i + 1)
+			return &InlineMath{Formula: string(line[1:i])}
+		}
+	}
+	return nil
+}
+
+type displayParser struct{}
+
+func (p *displayParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *displayParser) Open(_ ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], delim) {
+		return nil, parser.NoChildren
+	}
+
+	node := &DisplayMath{}
+	start := pos + len(delim)
+	rest := line[start:]
+	if end := bytes.Index(rest, delim); end >= 0 {
+		// single line formula, which must end the line
+		if !util.IsBlank(rest[end+len(delim):]) {
+			return nil, parser.NoChildren
+		}
+		node.Lines().Append(text.NewSegment(segment.Start+start, segment.Start+start+end))
+		node.closed = true
+		reader.Advance(segment.Len() - 1)
+		return node, parser.NoChildren
+	}
+
+	node.Lines().Append(text.NewSegment(segment.Start+start, segment.Stop))
+	reader.Advance(segment.Len() - 1)
+	return node, parser.NoChildren
+}
+
+func (p *displayParser) Continue(node ast.Node, reader text.Reader, _ parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if line == nil || node.(*DisplayMath).closed {
+		return parser.Close
+	}
+	if end := bytes.Index(line, delim); end >= 0 {
+		node.Lines().Append(text.NewSegment(segment.Start, segment.Start+end))
+		reader.Advance(segment.Len() - 1)
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - 1)
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *displayParser) Close(_ ast.Node, _ text.Reader, _ parser.Context) {}
+
+func (p *displayParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *displayParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that parses inline and display math.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			util.Prioritized(&displayParser{}, 150), //nolint: mnd
+		),
+		parser.WithInlineParsers(
+			util.Prioritized(&inlineParser{}, 150), //nolint: mnd
+		),
+	)
+}
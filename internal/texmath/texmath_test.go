@@ -0,0 +1,106 @@
+package texmath_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/texmath"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestInline(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{`O(n \log n)`, "O(n log n)"},
+		{`\sum_{i=0}^n x_i`, "∑ᵢ₌₀ⁿ xᵢ"},
+		{`\alpha \leq \beta \neq \gamma`, "α ≤ β ≠ γ"},
+		{`x^2 + y^2 = z^2`, "x² + y² = z²"},
+		{`e^{i\pi} = -1`, "e^(iπ) = −1"},
+		{`\frac{1}{2} + \frac{a+b}{c}`, "½ + (a + b)/c"},
+		{`\sqrt{x+1} \cdot \sqrt[3]{y}`, "√(x + 1) · ∛y"},
+		{`\mathbb{R}^n`, "ℝⁿ"},
+		{`\begin{pmatrix} a & b \\ c & d \end{pmatrix}`, "(a b; c d)"},
+		{`\text{if } x \in A`, "if x ∈ A"},
+		{`\unknown`, `\unknown`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := texmath.Inline(tc.in); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{`\sum_{i=0}^n x_i`, " n\n ∑  xᵢ\ni=0"},
+		{`\frac{a}{b+1}`, "   a\n───────\n b + 1"},
+		{`\sqrt{x}`, " _\n√x"},
+		{`\begin{bmatrix} 1 & 0 \\ 0 & 1 \end{bmatrix}`, "⎡1  0⎤\n⎣0  1⎦"},
+		{`\left( \frac{a}{b} \right)^2`, "⎛ a ⎞\n⎜───⎟²\n⎝ b ⎠"},
+		{`a &= b \\ &= c`, "a = b\n  = c"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := strings.Join(texmath.Display(tc.in), "\n"); got != tc.expected {
+				t.Errorf("expected\n%s\ngot\n%s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParser(t *testing.T) {
+	tests := []struct {
+		in      string
+		kind    ast.NodeKind
+		formula string
+	}{
+		{"$$x^2$$", texmath.KindDisplayMath, "x^2"},
+		{"$$\n\\frac{a}{b}\n$$", texmath.KindDisplayMath, `\frac{a}{b}`},
+		{"a $x$ b", texmath.KindInlineMath, "x"},
+		{"a $$x$$ b", texmath.KindInlineMath, "x"},
+		{"costs $5 and $ 10", ast.KindText, ""},
+		{"$x $", ast.KindText, ""},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(texmath.New()))
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			src := []byte(tc.in)
+			doc := md.Parser().Parse(text.NewReader(src))
+
+			var found ast.Node
+			_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+				if entering && found == nil && (n.Kind() == texmath.KindInlineMath || n.Kind() == texmath.KindDisplayMath) {
+					found = n
+				}
+				return ast.WalkContinue, nil
+			})
+
+			switch n := found.(type) {
+			case nil:
+				if tc.kind != ast.KindText {
+					t.Fatalf("expected %s, found no math", tc.kind)
+				}
+			case *texmath.InlineMath:
+				if tc.kind != texmath.KindInlineMath || n.Formula != tc.formula {
+					t.Errorf("expected %s %q, got inline math %q", tc.kind, tc.formula, n.Formula)
+				}
+			case *texmath.DisplayMath:
+				if f := n.Formula(src); tc.kind != texmath.KindDisplayMath || f != tc.formula {
+					t.Errorf("expected %s %q, got display math %q", tc.kind, tc.formula, f)
+				}
+			}
+		})
+	}
+}
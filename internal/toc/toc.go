@@ -0,0 +1,155 @@
+// Package toc provides a goldmark extension that replaces [TOC] placeholders
+// with a table of contents node.
+package toc
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/glamour/internal/frontmatter"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindTableOfContents is the NodeKind of a TableOfContents node.
+var KindTableOfContents = ast.NewNodeKind("TableOfContents")
+
+// TableOfContents is a block node marking where a table of contents should be
+// rendered. The entries themselves are collected from the document's headings
+// at render time.
+type TableOfContents struct {
+	ast.BaseBlock
+}
+
+// Kind implements ast.Node.Kind.
+func (n *TableOfContents) Kind() ast.NodeKind {
+	return KindTableOfContents
+}
+
+// Dump implements ast.Node.Dump.
+func (n *TableOfContents) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// NewTableOfContents returns a new TableOfContents node.
+func NewTableOfContents() *TableOfContents {
+	return &TableOfContents{}
+}
+
+var placeholder = []byte("[TOC]")
+
+type transformer struct{}
+
+// Slug returns the anchor GitHub generates for a heading: its text in lower
+// case, without punctuation and with hyphens instead of spaces. Letters and
+// digits of every script are kept.
+func Slug(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsMark(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// headingText returns the plain text of a heading, without any inline
+// markup.
+func headingText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			b.Write(n.Segment.Value(source))
+		case *ast.String:
+			b.Write(n.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
+
+// setHeadingIDs gives every heading a unique ID from its slug, numbering
+// repeated slugs like GitHub does: "intro", "intro-1", "intro-2".
+func setHeadingIDs(doc *ast.Document, source []byte) {
+	seen := map[string]int{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Kind() != ast.KindHeading {
+			return ast.WalkContinue, nil
+		}
+		id := Slug(headingText(n, source))
+		if id == "" {
+			id = "heading"
+		}
+		if count, ok := seen[id]; ok {
+			seen[id] = count + 1
+			id += "-" + strconv.Itoa(count)
+		} else {
+			seen[id] = 1
+		}
+		n.SetAttributeString("id", []byte(id))
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// Transform replaces every paragraph consisting solely of a [TOC] placeholder
+// with a TableOfContents node. If the document has no placeholder, a table of
+// contents is inserted at the top of the document. Headings get IDs from
+// their slugs.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	setHeadingIDs(doc, source)
+
+	var placeholders []ast.Node
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		if n.Kind() != ast.KindParagraph || n.Lines().Len() != 1 {
+			continue
+		}
+		line := n.Lines().At(0)
+		if bytes.Equal(bytes.TrimSpace(line.Value(source)), placeholder) {
+			placeholders = append(placeholders, n)
+		}
+	}
+
+	if len(placeholders) == 0 {
+		first := doc.FirstChild()
+		if first != nil && first.Kind() == frontmatter.KindFrontMatter {
+			// keep the table of contents below the document's front matter
+			doc.InsertAfter(doc, first, NewTableOfContents())
+			return
+		}
+		if first != nil {
+			doc.InsertBefore(doc, first, NewTableOfContents())
+		}
+		return
+	}
+	for _, n := range placeholders {
+		doc.ReplaceChild(doc, n, NewTableOfContents())
+	}
+}
+
+type extension struct{}
+
+// New returns a goldmark extension that renders [TOC] placeholders as a table
+// of contents.
+func New() goldmark.Extender {
+	return &extension{}
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&transformer{}, 100), //nolint: mnd
+	))
+}
@@ -0,0 +1,109 @@
+package toc_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/glamour/internal/frontmatter"
+	"github.com/charmbracelet/glamour/internal/toc"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestPlaceholder(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		kinds []ast.NodeKind
+	}{
+		{"placeholder", "# A\n\n[TOC]\n\n## B", []ast.NodeKind{ast.KindHeading, toc.KindTableOfContents, ast.KindHeading}},
+		{"indented placeholder", "  [TOC]  \n\n# A", []ast.NodeKind{toc.KindTableOfContents, ast.KindHeading}},
+		{"several placeholders", "[TOC]\n\n# A\n\n[TOC]", []ast.NodeKind{toc.KindTableOfContents, ast.KindHeading, toc.KindTableOfContents}},
+		{"no placeholder", "# A\n\nText.", []ast.NodeKind{toc.KindTableOfContents, ast.KindHeading, ast.KindParagraph}},
+		{"placeholder in text", "See [TOC] below.\n\n# A", []ast.NodeKind{toc.KindTableOfContents, ast.KindParagraph, ast.KindHeading}},
+		{"front matter", "---\ntitle: T\n---\n# A", []ast.NodeKind{frontmatter.KindFrontMatter, toc.KindTableOfContents, ast.KindHeading}},
+		{"empty document", "", nil},
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(frontmatter.New(), toc.New()))
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tc.in)))
+
+			var kinds []ast.NodeKind
+			for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+				kinds = append(kinds, n.Kind())
+			}
+			if len(kinds) != len(tc.kinds) {
+				t.Fatalf("expected %v, got %v", tc.kinds, kinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tc.kinds[i] {
+					t.Fatalf("expected %v, got %v", tc.kinds, kinds)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadingIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		ids  []string
+	}{
+		{"duplicates", "# Intro\n\n## Intro\n\n### Intro", []string{"intro", "intro-1", "intro-2"}},
+		{"punctuation", "# Hello, World!\n\n## What's new? (v2.0)", []string{"hello-world", "whats-new-v20"}},
+		{"unicode", "# Über Café\n\n## 日本語", []string{"über-café", "日本語"}},
+		{"formatting", "# The `toc` *package*", []string{"the-toc-package"}},
+	}
+
+	md := goldmark.New(
+		goldmark.WithExtensions(toc.New()),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tc.in)))
+
+			var ids []string
+			for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+				if n.Kind() != ast.KindHeading {
+					continue
+				}
+				id, _ := n.AttributeString("id")
+				b, _ := id.([]byte)
+				ids = append(ids, string(b))
+			}
+			if len(ids) != len(tc.ids) {
+				t.Fatalf("expected %q, got %q", tc.ids, ids)
+			}
+			for i := range ids {
+				if ids[i] != tc.ids[i] {
+					t.Errorf("expected %q, got %q", tc.ids, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Getting Started", "getting-started"},
+		{"  Trimmed  ", "trimmed"},
+		{"C++ & Go: a comparison", "c--go-a-comparison"},
+		{"snake_case and kebab-case", "snake_case-and-kebab-case"},
+		{"Ünïcödé Ñame", "ünïcödé-ñame"},
+		{"Привет, мир", "привет-мир"},
+		{"🎉 Party", "-party"},
+		{"!!!", ""},
+	}
+	for _, tc := range tests {
+		if got := toc.Slug(tc.in); got != tc.want {
+			t.Errorf("Slug(%q): expected %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
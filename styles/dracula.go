@@ -3,12 +3,31 @@ package styles
 import "github.com/charmbracelet/glamour/ansi"
 
 // DraculaStyleConfig is the dracula style.
-var DraculaStyleConfig = ansi.StyleConfig{
+var DraculaStyleConfig = resolved(draculaStyle)
+
+// draculaStyle is the dracula style, its colors referring to its palette.
+var draculaStyle = ansi.StyleConfig{
+	Palette: map[string]string{
+		"text":      "#f8f8f2",
+		"heading":   "#bd93f9",
+		"muted":     "#6272A4",
+		"border":    "#6272A4",
+		"link":      "#8be9fd",
+		"link_text": "#ff79c6",
+		"code":      "#50fa7b",
+		"success":   "#50fa7b",
+		"error":     "#ff5555",
+		"note":      "#8be9fd",
+		"tip":       "#50fa7b",
+		"important": "#bd93f9",
+		"warning":   "#f1fa8c",
+		"caution":   "#ff5555",
+	},
 	Document: ansi.StyleBlock{
 		StylePrimitive: ansi.StylePrimitive{
 			BlockPrefix: "\n",
 			BlockSuffix: "\n",
-			Color:       stringPtr("#f8f8f2"),
+			Color:       stringPtr("$text"),
 		},
 		Margin: uintPtr(defaultMargin),
 	},
@@ -23,14 +42,14 @@ var DraculaStyleConfig = ansi.StyleConfig{
 		LevelIndent: defaultMargin,
 		StyleBlock: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
-				Color: stringPtr("#f8f8f2"),
+				Color: stringPtr("$text"),
 			},
 		},
 	},
 	Heading: ansi.StyleBlock{
 		StylePrimitive: ansi.StylePrimitive{
 			BlockSuffix: "\n",
-			Color:       stringPtr("#bd93f9"),
+			Color:       stringPtr("$heading"),
 			Bold:        boolPtr(true),
 		},
 	},
@@ -75,8 +94,49 @@ var DraculaStyleConfig = ansi.StyleConfig{
 		Bold:  boolPtr(true),
 		Color: stringPtr("#ffb86c"),
 	},
+	Kbd: ansi.StylePrimitive{
+		Prefix:          " ",
+		Suffix:          " ",
+		Color:           stringPtr("#f8f8f2"),
+		BackgroundColor: stringPtr("#44475a"),
+	},
+	Mark: ansi.StylePrimitive{
+		Color:           stringPtr("#282a36"),
+		BackgroundColor: stringPtr("#f1fa8c"),
+	},
+	Ins: ansi.StylePrimitive{
+		Underline: boolPtr(true),
+	},
+	Annotation: ansi.StylePrimitive{
+		Underline:      boolPtr(true),
+		UnderlineStyle: stringPtr("curly"),
+		UnderlineColor: stringPtr("$error"),
+	},
+	Sub: ansi.StylePrimitive{
+		Prefix: "_(",
+		Suffix: ")",
+	},
+	Sup: ansi.StylePrimitive{
+		Prefix: "^(",
+		Suffix: ")",
+	},
+	Spoiler: ansi.StylePrimitive{
+		Conceal:         boolPtr(true),
+		BackgroundColor: stringPtr("#44475a"),
+	},
+	Summary: ansi.StylePrimitive{
+		Bold:  boolPtr(true),
+		Color: stringPtr("$heading"),
+	},
+	Details: ansi.StyleDetails{
+		StyleBlock: ansi.StyleBlock{
+			Indent: uintPtr(2),
+		},
+		Collapsed: "▶ ",
+		Expanded:  "▼ ",
+	},
 	HorizontalRule: ansi.StylePrimitive{
-		Color:  stringPtr("#6272A4"),
+		Color:  stringPtr("$muted"),
 		Format: "\n--------\n",
 	},
 	Item: ansi.StylePrimitive{
@@ -92,11 +152,11 @@ var DraculaStyleConfig = ansi.StyleConfig{
 		Unticked:       "[ ] ",
 	},
 	Link: ansi.StylePrimitive{
-		Color:     stringPtr("#8be9fd"),
+		Color:     stringPtr("$link"),
 		Underline: boolPtr(true),
 	},
 	LinkText: ansi.StylePrimitive{
-		Color: stringPtr("#ff79c6"),
+		Color: stringPtr("$link_text"),
 	},
 	Image: ansi.StylePrimitive{
 		Color:     stringPtr("#8be9fd"),
@@ -108,7 +168,7 @@ var DraculaStyleConfig = ansi.StyleConfig{
 	},
 	Code: ansi.StyleBlock{
 		StylePrimitive: ansi.StylePrimitive{
-			Color: stringPtr("#50fa7b"),
+			Color: stringPtr("$code"),
 		},
 	},
 	CodeBlock: ansi.StyleCodeBlock{
@@ -118,6 +178,43 @@ var DraculaStyleConfig = ansi.StyleConfig{
 			},
 			Margin: uintPtr(defaultMargin),
 		},
+		Title: ansi.StylePrimitive{
+			Color: stringPtr("$text"),
+			Bold:  boolPtr(true),
+		},
+		LineNumber: ansi.StylePrimitive{
+			Color:  stringPtr("$muted"),
+			Suffix: " │ ",
+		},
+		Highlight: ansi.StylePrimitive{
+			BackgroundColor: stringPtr("#44475a"),
+		},
+		WrapMarker: "↪",
+		Ellipsis:   "…",
+		Diff: ansi.StyleDiff{
+			Added: ansi.StylePrimitive{
+				Color:           stringPtr("$success"),
+				BackgroundColor: stringPtr("#233a2a"),
+			},
+			Removed: ansi.StylePrimitive{
+				Color:           stringPtr("$error"),
+				BackgroundColor: stringPtr("#3d2430"),
+			},
+			Hunk: ansi.StylePrimitive{
+				Color: stringPtr("$heading"),
+			},
+			Header: ansi.StylePrimitive{
+				Color: stringPtr("$text"),
+				Bold:  boolPtr(true),
+			},
+			SyntaxHighlighting: true,
+		},
+		BorderColor: stringPtr("$border"),
+		Label: ansi.StylePrimitive{
+			Color:  stringPtr("#f8f8f2"),
+			Prefix: " ",
+			Suffix: " ",
+		},
 		Chroma: &ansi.Chroma{
 			Text: ansi.StylePrimitive{
 				Color: stringPtr("#f8f8f2"),
@@ -210,6 +307,37 @@ var DraculaStyleConfig = ansi.StyleConfig{
 			StylePrimitive: ansi.StylePrimitive{},
 		},
 	},
+	TOC: ansi.StyleTOC{
+		ID: ansi.StylePrimitive{
+			Color: stringPtr("$muted"),
+		},
+		LevelIndent: defaultListIndent,
+	},
+	Alerts: alertStyles("│ ", true, "$note", "$tip", "$important", "$warning", "$caution"),
+	FrontMatter: ansi.StyleFrontMatter{
+		Title: ansi.StylePrimitive{
+			Color: stringPtr("$heading"),
+			Bold:  boolPtr(true),
+		},
+		Date: ansi.StylePrimitive{
+			Color: stringPtr("#6272A4"),
+		},
+		Tag: ansi.StylePrimitive{
+			Color:  stringPtr("#50fa7b"),
+			Prefix: "#",
+		},
+		Separator: " · ",
+	},
+	Math: ansi.StyleMath{
+		StyleBlock: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color: stringPtr("#8be9fd"),
+			},
+		},
+		Inline: ansi.StylePrimitive{
+			Color: stringPtr("#8be9fd"),
+		},
+	},
 	DefinitionDescription: ansi.StylePrimitive{
 		BlockPrefix: "\n🠶 ",
 	},
@@ -96,6 +96,41 @@ var (
 			BlockPrefix: "**",
 			BlockSuffix: "**",
 		},
+		Kbd: ansi.StylePrimitive{
+			Prefix: "[",
+			Suffix: "]",
+		},
+		Mark: ansi.StylePrimitive{
+			Prefix: "==",
+			Suffix: "==",
+		},
+		Ins: ansi.StylePrimitive{
+			Prefix: "++",
+			Suffix: "++",
+		},
+		Annotation: ansi.StylePrimitive{
+			Prefix: "_",
+			Suffix: "_",
+		},
+		Sub: ansi.StylePrimitive{
+			Prefix: "_(",
+			Suffix: ")",
+		},
+		Sup: ansi.StylePrimitive{
+			Prefix: "^(",
+			Suffix: ")",
+		},
+		Spoiler: ansi.StylePrimitive{
+			Prefix: "||",
+			Suffix: "||",
+		},
+		Details: ansi.StyleDetails{
+			StyleBlock: ansi.StyleBlock{
+				Indent: uintPtr(2),
+			},
+			Collapsed: "> ",
+			Expanded:  "v ",
+		},
 		HorizontalRule: ansi.StylePrimitive{
 			Format: "\n--------\n",
 		},
@@ -122,24 +157,71 @@ var (
 			StyleBlock: ansi.StyleBlock{
 				Margin: uintPtr(defaultMargin),
 			},
+			Title: ansi.StylePrimitive{
+				Prefix: "[",
+				Suffix: "]",
+			},
+			LineNumber: ansi.StylePrimitive{
+				Suffix: " | ",
+			},
+			Highlight: ansi.StylePrimitive{
+				Prefix: "> ",
+			},
+			WrapMarker: "+",
+			Ellipsis:   "...",
+			Label: ansi.StylePrimitive{
+				Prefix: " ",
+				Suffix: " ",
+			},
 		},
 		Table: ansi.StyleTable{
 			CenterSeparator: stringPtr("|"),
 			ColumnSeparator: stringPtr("|"),
 			RowSeparator:    stringPtr("-"),
 		},
+		TOC: ansi.StyleTOC{
+			LevelIndent: defaultListIndent,
+		},
+		Alerts: alertStyles("| ", false, "", "", "", "", ""),
+		FrontMatter: ansi.StyleFrontMatter{
+			Tag: ansi.StylePrimitive{
+				Prefix: "#",
+			},
+			Separator: " | ",
+		},
 		DefinitionDescription: ansi.StylePrimitive{
 			BlockPrefix: "\n* ",
 		},
 	}
 
 	// DarkStyleConfig is the default dark style.
-	DarkStyleConfig = ansi.StyleConfig{
+	DarkStyleConfig = resolved(darkStyle)
+
+	// darkStyle is the default dark style, its colors referring to its palette.
+	darkStyle = ansi.StyleConfig{
+		Palette: map[string]string{
+			"text":            "252",
+			"heading":         "39",
+			"accent":          "63",
+			"muted":           "240",
+			"border":          "238",
+			"link":            "30",
+			"link_text":       "35",
+			"code":            "203",
+			"code_background": "236",
+			"success":         "42",
+			"error":           "203",
+			"note":            "33",
+			"tip":             "35",
+			"important":       "141",
+			"warning":         "214",
+			"caution":         "203",
+		},
 		Document: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockPrefix: "\n",
 				BlockSuffix: "\n",
-				Color:       stringPtr("252"),
+				Color:       stringPtr("$text"),
 			},
 			Margin: uintPtr(defaultMargin),
 		},
@@ -154,7 +236,7 @@ var (
 		Heading: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockSuffix: "\n",
-				Color:       stringPtr("39"),
+				Color:       stringPtr("$heading"),
 				Bold:        boolPtr(true),
 			},
 		},
@@ -163,7 +245,7 @@ var (
 				Prefix:          " ",
 				Suffix:          " ",
 				Color:           stringPtr("228"),
-				BackgroundColor: stringPtr("63"),
+				BackgroundColor: stringPtr("$accent"),
 				Bold:            boolPtr(true),
 			},
 		},
@@ -203,8 +285,48 @@ var (
 		Strong: ansi.StylePrimitive{
 			Bold: boolPtr(true),
 		},
+		Kbd: ansi.StylePrimitive{
+			Prefix:          " ",
+			Suffix:          " ",
+			Color:           stringPtr("252"),
+			BackgroundColor: stringPtr("238"),
+		},
+		Mark: ansi.StylePrimitive{
+			Color:           stringPtr("234"),
+			BackgroundColor: stringPtr("220"),
+		},
+		Ins: ansi.StylePrimitive{
+			Underline: boolPtr(true),
+		},
+		Annotation: ansi.StylePrimitive{
+			Underline:      boolPtr(true),
+			UnderlineStyle: stringPtr("curly"),
+			UnderlineColor: stringPtr("$error"),
+		},
+		Sub: ansi.StylePrimitive{
+			Prefix: "_(",
+			Suffix: ")",
+		},
+		Sup: ansi.StylePrimitive{
+			Prefix: "^(",
+			Suffix: ")",
+		},
+		Spoiler: ansi.StylePrimitive{
+			Conceal:         boolPtr(true),
+			BackgroundColor: stringPtr("240"),
+		},
+		Summary: ansi.StylePrimitive{
+			Bold: boolPtr(true),
+		},
+		Details: ansi.StyleDetails{
+			StyleBlock: ansi.StyleBlock{
+				Indent: uintPtr(2),
+			},
+			Collapsed: "▶ ",
+			Expanded:  "▼ ",
+		},
 		HorizontalRule: ansi.StylePrimitive{
-			Color:  stringPtr("240"),
+			Color:  stringPtr("$muted"),
 			Format: "\n--------\n",
 		},
 		Item: ansi.StylePrimitive{
@@ -219,11 +341,11 @@ var (
 			Unticked:       "[ ] ",
 		},
 		Link: ansi.StylePrimitive{
-			Color:     stringPtr("30"),
+			Color:     stringPtr("$link"),
 			Underline: boolPtr(true),
 		},
 		LinkText: ansi.StylePrimitive{
-			Color: stringPtr("35"),
+			Color: stringPtr("$link_text"),
 			Bold:  boolPtr(true),
 		},
 		Image: ansi.StylePrimitive{
@@ -238,8 +360,8 @@ var (
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix:          " ",
 				Suffix:          " ",
-				Color:           stringPtr("203"),
-				BackgroundColor: stringPtr("236"),
+				Color:           stringPtr("$code"),
+				BackgroundColor: stringPtr("$code_background"),
 			},
 		},
 		CodeBlock: ansi.StyleCodeBlock{
@@ -249,6 +371,43 @@ var (
 				},
 				Margin: uintPtr(defaultMargin),
 			},
+			Title: ansi.StylePrimitive{
+				Color: stringPtr("$text"),
+				Bold:  boolPtr(true),
+			},
+			LineNumber: ansi.StylePrimitive{
+				Color:  stringPtr("$muted"),
+				Suffix: " │ ",
+			},
+			Highlight: ansi.StylePrimitive{
+				BackgroundColor: stringPtr("237"),
+			},
+			WrapMarker: "↪",
+			Ellipsis:   "…",
+			Diff: ansi.StyleDiff{
+				Added: ansi.StylePrimitive{
+					Color:           stringPtr("$success"),
+					BackgroundColor: stringPtr("22"),
+				},
+				Removed: ansi.StylePrimitive{
+					Color:           stringPtr("$error"),
+					BackgroundColor: stringPtr("52"),
+				},
+				Hunk: ansi.StylePrimitive{
+					Color: stringPtr("$heading"),
+				},
+				Header: ansi.StylePrimitive{
+					Color: stringPtr("$text"),
+					Bold:  boolPtr(true),
+				},
+				SyntaxHighlighting: true,
+			},
+			BorderColor: stringPtr("$border"),
+			Label: ansi.StylePrimitive{
+				Color:  stringPtr("244"),
+				Prefix: " ",
+				Suffix: " ",
+			},
 			Chroma: &ansi.Chroma{
 				Text: ansi.StylePrimitive{
 					Color: stringPtr("#C4C4C4"),
@@ -338,18 +497,70 @@ var (
 				StylePrimitive: ansi.StylePrimitive{},
 			},
 		},
+		TOC: ansi.StyleTOC{
+			ID: ansi.StylePrimitive{
+				Color: stringPtr("$muted"),
+			},
+			LevelIndent: defaultListIndent,
+		},
+		Alerts: alertStyles("│ ", true, "$note", "$tip", "$important", "$warning", "$caution"),
+		FrontMatter: ansi.StyleFrontMatter{
+			Title: ansi.StylePrimitive{
+				Color: stringPtr("228"),
+				Bold:  boolPtr(true),
+			},
+			Date: ansi.StylePrimitive{
+				Color: stringPtr("244"),
+			},
+			Tag: ansi.StylePrimitive{
+				Color:  stringPtr("35"),
+				Prefix: "#",
+			},
+			Separator: " · ",
+		},
+		Math: ansi.StyleMath{
+			StyleBlock: ansi.StyleBlock{
+				StylePrimitive: ansi.StylePrimitive{
+					Color: stringPtr("117"),
+				},
+			},
+			Inline: ansi.StylePrimitive{
+				Color: stringPtr("117"),
+			},
+		},
 		DefinitionDescription: ansi.StylePrimitive{
 			BlockPrefix: "\n🠶 ",
 		},
 	}
 
 	// LightStyleConfig is the default light style.
-	LightStyleConfig = ansi.StyleConfig{
+	LightStyleConfig = resolved(lightStyle)
+
+	// lightStyle is the default light style, its colors referring to its palette.
+	lightStyle = ansi.StyleConfig{
+		Palette: map[string]string{
+			"text":            "234",
+			"heading":         "27",
+			"accent":          "63",
+			"muted":           "249",
+			"border":          "250",
+			"link":            "36",
+			"link_text":       "29",
+			"code":            "203",
+			"code_background": "254",
+			"success":         "28",
+			"error":           "160",
+			"note":            "26",
+			"tip":             "28",
+			"important":       "91",
+			"warning":         "136",
+			"caution":         "160",
+		},
 		Document: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockPrefix: "\n",
 				BlockSuffix: "\n",
-				Color:       stringPtr("234"),
+				Color:       stringPtr("$text"),
 			},
 			Margin: uintPtr(defaultMargin),
 		},
@@ -364,7 +575,7 @@ var (
 		Heading: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockSuffix: "\n",
-				Color:       stringPtr("27"),
+				Color:       stringPtr("$heading"),
 				Bold:        boolPtr(true),
 			},
 		},
@@ -373,7 +584,7 @@ var (
 				Prefix:          " ",
 				Suffix:          " ",
 				Color:           stringPtr("228"),
-				BackgroundColor: stringPtr("63"),
+				BackgroundColor: stringPtr("$accent"),
 				Bold:            boolPtr(true),
 			},
 		},
@@ -412,8 +623,48 @@ var (
 		Strong: ansi.StylePrimitive{
 			Bold: boolPtr(true),
 		},
+		Kbd: ansi.StylePrimitive{
+			Prefix:          " ",
+			Suffix:          " ",
+			Color:           stringPtr("236"),
+			BackgroundColor: stringPtr("252"),
+		},
+		Mark: ansi.StylePrimitive{
+			Color:           stringPtr("234"),
+			BackgroundColor: stringPtr("228"),
+		},
+		Ins: ansi.StylePrimitive{
+			Underline: boolPtr(true),
+		},
+		Annotation: ansi.StylePrimitive{
+			Underline:      boolPtr(true),
+			UnderlineStyle: stringPtr("curly"),
+			UnderlineColor: stringPtr("$error"),
+		},
+		Sub: ansi.StylePrimitive{
+			Prefix: "_(",
+			Suffix: ")",
+		},
+		Sup: ansi.StylePrimitive{
+			Prefix: "^(",
+			Suffix: ")",
+		},
+		Spoiler: ansi.StylePrimitive{
+			Conceal:         boolPtr(true),
+			BackgroundColor: stringPtr("250"),
+		},
+		Summary: ansi.StylePrimitive{
+			Bold: boolPtr(true),
+		},
+		Details: ansi.StyleDetails{
+			StyleBlock: ansi.StyleBlock{
+				Indent: uintPtr(2),
+			},
+			Collapsed: "▶ ",
+			Expanded:  "▼ ",
+		},
 		HorizontalRule: ansi.StylePrimitive{
-			Color:  stringPtr("249"),
+			Color:  stringPtr("$muted"),
 			Format: "\n--------\n",
 		},
 		Item: ansi.StylePrimitive{
@@ -428,11 +679,11 @@ var (
 			Unticked:       "[ ] ",
 		},
 		Link: ansi.StylePrimitive{
-			Color:     stringPtr("36"),
+			Color:     stringPtr("$link"),
 			Underline: boolPtr(true),
 		},
 		LinkText: ansi.StylePrimitive{
-			Color: stringPtr("29"),
+			Color: stringPtr("$link_text"),
 			Bold:  boolPtr(true),
 		},
 		Image: ansi.StylePrimitive{
@@ -447,8 +698,8 @@ var (
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix:          " ",
 				Suffix:          " ",
-				Color:           stringPtr("203"),
-				BackgroundColor: stringPtr("254"),
+				Color:           stringPtr("$code"),
+				BackgroundColor: stringPtr("$code_background"),
 			},
 		},
 		CodeBlock: ansi.StyleCodeBlock{
@@ -458,6 +709,43 @@ var (
 				},
 				Margin: uintPtr(defaultMargin),
 			},
+			Title: ansi.StylePrimitive{
+				Color: stringPtr("235"),
+				Bold:  boolPtr(true),
+			},
+			LineNumber: ansi.StylePrimitive{
+				Color:  stringPtr("$muted"),
+				Suffix: " │ ",
+			},
+			Highlight: ansi.StylePrimitive{
+				BackgroundColor: stringPtr("254"),
+			},
+			WrapMarker: "↪",
+			Ellipsis:   "…",
+			Diff: ansi.StyleDiff{
+				Added: ansi.StylePrimitive{
+					Color:           stringPtr("$success"),
+					BackgroundColor: stringPtr("194"),
+				},
+				Removed: ansi.StylePrimitive{
+					Color:           stringPtr("$error"),
+					BackgroundColor: stringPtr("224"),
+				},
+				Hunk: ansi.StylePrimitive{
+					Color: stringPtr("$heading"),
+				},
+				Header: ansi.StylePrimitive{
+					Color: stringPtr("235"),
+					Bold:  boolPtr(true),
+				},
+				SyntaxHighlighting: true,
+			},
+			BorderColor: stringPtr("$border"),
+			Label: ansi.StylePrimitive{
+				Color:  stringPtr("242"),
+				Prefix: " ",
+				Suffix: " ",
+			},
 			Chroma: &ansi.Chroma{
 				Text: ansi.StylePrimitive{
 					Color: stringPtr("#2A2A2A"),
@@ -547,13 +835,62 @@ var (
 				StylePrimitive: ansi.StylePrimitive{},
 			},
 		},
+		TOC: ansi.StyleTOC{
+			ID: ansi.StylePrimitive{
+				Color: stringPtr("246"),
+			},
+			LevelIndent: defaultListIndent,
+		},
+		Alerts: alertStyles("│ ", true, "$note", "$tip", "$important", "$warning", "$caution"),
+		FrontMatter: ansi.StyleFrontMatter{
+			Title: ansi.StylePrimitive{
+				Color: stringPtr("$heading"),
+				Bold:  boolPtr(true),
+			},
+			Date: ansi.StylePrimitive{
+				Color: stringPtr("242"),
+			},
+			Tag: ansi.StylePrimitive{
+				Color:  stringPtr("28"),
+				Prefix: "#",
+			},
+			Separator: " · ",
+		},
+		Math: ansi.StyleMath{
+			StyleBlock: ansi.StyleBlock{
+				StylePrimitive: ansi.StylePrimitive{
+					Color: stringPtr("31"),
+				},
+			},
+			Inline: ansi.StylePrimitive{
+				Color: stringPtr("31"),
+			},
+		},
 		DefinitionDescription: ansi.StylePrimitive{
 			BlockPrefix: "\n🠶 ",
 		},
 	}
 
 	// PinkStyleConfig is the default pink style.
-	PinkStyleConfig = ansi.StyleConfig{
+	PinkStyleConfig = resolved(pinkStyle)
+
+	// pinkStyle is the default pink style, its colors referring to its palette.
+	pinkStyle = ansi.StyleConfig{
+		Palette: map[string]string{
+			"heading":         "212",
+			"muted":           "212",
+			"border":          "238",
+			"link":            "99",
+			"code":            "212",
+			"code_background": "236",
+			"success":         "42",
+			"error":           "203",
+			"note":            "39",
+			"tip":             "42",
+			"important":       "177",
+			"warning":         "214",
+			"caution":         "203",
+		},
 		Document: ansi.StyleBlock{
 			Margin: uintPtr(defaultMargin),
 		},
@@ -567,7 +904,7 @@ var (
 		Heading: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockSuffix: "\n",
-				Color:       stringPtr("212"),
+				Color:       stringPtr("$heading"),
 				Bold:        boolPtr(true),
 			},
 		},
@@ -614,8 +951,48 @@ var (
 		Strong: ansi.StylePrimitive{
 			Bold: boolPtr(true),
 		},
+		Kbd: ansi.StylePrimitive{
+			Prefix:          " ",
+			Suffix:          " ",
+			Color:           stringPtr("212"),
+			BackgroundColor: stringPtr("236"),
+		},
+		Mark: ansi.StylePrimitive{
+			Color:           stringPtr("236"),
+			BackgroundColor: stringPtr("212"),
+		},
+		Ins: ansi.StylePrimitive{
+			Underline: boolPtr(true),
+		},
+		Annotation: ansi.StylePrimitive{
+			Underline:      boolPtr(true),
+			UnderlineStyle: stringPtr("curly"),
+			UnderlineColor: stringPtr("$error"),
+		},
+		Sub: ansi.StylePrimitive{
+			Prefix: "_(",
+			Suffix: ")",
+		},
+		Sup: ansi.StylePrimitive{
+			Prefix: "^(",
+			Suffix: ")",
+		},
+		Spoiler: ansi.StylePrimitive{
+			Conceal:         boolPtr(true),
+			BackgroundColor: stringPtr("236"),
+		},
+		Summary: ansi.StylePrimitive{
+			Bold: boolPtr(true),
+		},
+		Details: ansi.StyleDetails{
+			StyleBlock: ansi.StyleBlock{
+				Indent: uintPtr(2),
+			},
+			Collapsed: "▶ ",
+			Expanded:  "▼ ",
+		},
 		HorizontalRule: ansi.StylePrimitive{
-			Color:  stringPtr("212"),
+			Color:  stringPtr("$muted"),
 			Format: "\n──────\n",
 		},
 		Item: ansi.StylePrimitive{
@@ -629,7 +1006,7 @@ var (
 			Unticked: "[ ] ",
 		},
 		Link: ansi.StylePrimitive{
-			Color:     stringPtr("99"),
+			Color:     stringPtr("$link"),
 			Underline: boolPtr(true),
 		},
 		LinkText: ansi.StylePrimitive{
@@ -643,13 +1020,81 @@ var (
 		},
 		Code: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
-				Color:           stringPtr("212"),
-				BackgroundColor: stringPtr("236"),
+				Color:           stringPtr("$code"),
+				BackgroundColor: stringPtr("$code_background"),
 				Prefix:          " ",
 				Suffix:          " ",
 			},
 		},
-		Table:          ansi.StyleTable{},
+		CodeBlock: ansi.StyleCodeBlock{
+			Title: ansi.StylePrimitive{
+				Color: stringPtr("212"),
+				Bold:  boolPtr(true),
+			},
+			LineNumber: ansi.StylePrimitive{
+				Color:  stringPtr("240"),
+				Suffix: " │ ",
+			},
+			Highlight: ansi.StylePrimitive{
+				BackgroundColor: stringPtr("236"),
+			},
+			WrapMarker: "↪",
+			Ellipsis:   "…",
+			Diff: ansi.StyleDiff{
+				Added: ansi.StylePrimitive{
+					Color:           stringPtr("$success"),
+					BackgroundColor: stringPtr("22"),
+				},
+				Removed: ansi.StylePrimitive{
+					Color:           stringPtr("204"),
+					BackgroundColor: stringPtr("52"),
+				},
+				Hunk: ansi.StylePrimitive{
+					Color: stringPtr("$heading"),
+				},
+				Header: ansi.StylePrimitive{
+					Bold: boolPtr(true),
+				},
+			},
+			BorderColor: stringPtr("$border"),
+			Label: ansi.StylePrimitive{
+				Color:  stringPtr("212"),
+				Prefix: " ",
+				Suffix: " ",
+			},
+		},
+		Table: ansi.StyleTable{},
+		TOC: ansi.StyleTOC{
+			ID: ansi.StylePrimitive{
+				Color: stringPtr("$muted"),
+			},
+			LevelIndent: defaultListIndent,
+		},
+		Alerts: alertStyles("│ ", true, "$note", "$tip", "$important", "$warning", "$caution"),
+		FrontMatter: ansi.StyleFrontMatter{
+			Title: ansi.StylePrimitive{
+				Color: stringPtr("$heading"),
+				Bold:  boolPtr(true),
+			},
+			Date: ansi.StylePrimitive{
+				Color: stringPtr("244"),
+			},
+			Tag: ansi.StylePrimitive{
+				Color:  stringPtr("213"),
+				Prefix: "#",
+			},
+			Separator: " · ",
+		},
+		Math: ansi.StyleMath{
+			StyleBlock: ansi.StyleBlock{
+				StylePrimitive: ansi.StylePrimitive{
+					Color: stringPtr("213"),
+				},
+			},
+			Inline: ansi.StylePrimitive{
+				Color: stringPtr("213"),
+			},
+		},
 		DefinitionList: ansi.StyleBlock{},
 		DefinitionTerm: ansi.StylePrimitive{},
 		DefinitionDescription: ansi.StylePrimitive{
@@ -674,8 +1119,66 @@ var (
 		DraculaStyle:    &DraculaStyleConfig,
 		TokyoNightStyle: &TokyoNightStyleConfig,
 	}
+
+	// DefaultStyleTemplates are the default styles with their colors
+	// referring to their palette, as in "$accent", so resolving them with
+	// ansi.ResolvePalette can recolor them.
+	DefaultStyleTemplates = map[string]*ansi.StyleConfig{
+		AsciiStyle: &ASCIIStyleConfig,
+		DarkStyle:  &darkStyle,
+		LightStyle: &lightStyle,
+		NoTTYStyle: &NoTTYStyleConfig,
+		PinkStyle:  &pinkStyle,
+
+		// Popular themes
+		DraculaStyle:    &draculaStyle,
+		TokyoNightStyle: &tokyoNightStyle,
+	}
 )
 
+// alertStyles returns the styles for GitHub-style alerts, drawing each alert's
+// border with indentToken in the given accent colors. Alerts without a color
+// are rendered without any text decoration.
+func alertStyles(indentToken string, icons bool, note, tip, important, warning, caution string) map[string]ansi.StyleAlert {
+	alert := func(icon, title, color string) ansi.StyleAlert {
+		s := ansi.StyleAlert{
+			StyleBlock: ansi.StyleBlock{
+				Indent:      uintPtr(1),
+				IndentToken: stringPtr(indentToken),
+			},
+			Title: title,
+		}
+		if icons {
+			s.Icon = icon
+		}
+		if color != "" {
+			s.Accent.Color = stringPtr(color)
+			s.Accent.Bold = boolPtr(true)
+		}
+		return s
+	}
+
+	return map[string]ansi.StyleAlert{
+		"note":      alert("ℹ", "Note", note),
+		"tip":       alert("★", "Tip", tip),
+		"important": alert("❢", "Important", important),
+		"warning":   alert("⚠", "Warning", warning),
+		"caution":   alert("✖", "Caution", caution),
+	}
+}
+
+// resolved returns s with its palette references resolved, so the exported
+// styles hold plain colors. A style referring to a color missing from its
+// palette is returned as is; renderers report the missing color when they
+// resolve it.
+func resolved(s ansi.StyleConfig) ansi.StyleConfig {
+	r, err := ansi.ResolvePalette(s, nil)
+	if err != nil {
+		return s
+	}
+	return r
+}
+
 func boolPtr(b bool) *bool       { return &b }
 func stringPtr(s string) *string { return &s }
 func uintPtr(u uint) *uint       { return &u }
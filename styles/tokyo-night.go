@@ -3,12 +3,31 @@ package styles
 import "github.com/charmbracelet/glamour/ansi"
 
 // TokyoNightStyleConfig is the tokyo night style.
-var TokyoNightStyleConfig = ansi.StyleConfig{
+var TokyoNightStyleConfig = resolved(tokyoNightStyle)
+
+// tokyoNightStyle is the tokyo night style, its colors referring to its palette.
+var tokyoNightStyle = ansi.StyleConfig{
+	Palette: map[string]string{
+		"text":      "#a9b1d6",
+		"heading":   "#bb9af7",
+		"muted":     "#565f89",
+		"border":    "#3b4261",
+		"link":      "#7aa2f7",
+		"link_text": "#2ac3de",
+		"code":      "#9ece6a",
+		"success":   "#9ece6a",
+		"error":     "#f7768e",
+		"note":      "#7aa2f7",
+		"tip":       "#9ece6a",
+		"important": "#bb9af7",
+		"warning":   "#e0af68",
+		"caution":   "#f7768e",
+	},
 	Document: ansi.StyleBlock{
 		StylePrimitive: ansi.StylePrimitive{
 			BlockPrefix: "\n",
 			BlockSuffix: "\n",
-			Color:       stringPtr("#a9b1d6"),
+			Color:       stringPtr("$text"),
 		},
 		Margin: uintPtr(defaultMargin),
 	},
@@ -20,7 +39,7 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 	List: ansi.StyleList{
 		StyleBlock: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
-				Color: stringPtr("#a9b1d6"),
+				Color: stringPtr("$text"),
 			},
 		},
 		LevelIndent: defaultListIndent,
@@ -28,7 +47,7 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 	Heading: ansi.StyleBlock{
 		StylePrimitive: ansi.StylePrimitive{
 			BlockSuffix: "\n",
-			Color:       stringPtr("#bb9af7"),
+			Color:       stringPtr("$heading"),
 			Bold:        boolPtr(true),
 		},
 	},
@@ -72,8 +91,49 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 	Strong: ansi.StylePrimitive{
 		Bold: boolPtr(true),
 	},
+	Kbd: ansi.StylePrimitive{
+		Prefix:          " ",
+		Suffix:          " ",
+		Color:           stringPtr("#c0caf5"),
+		BackgroundColor: stringPtr("#414868"),
+	},
+	Mark: ansi.StylePrimitive{
+		Color:           stringPtr("#1a1b26"),
+		BackgroundColor: stringPtr("#e0af68"),
+	},
+	Ins: ansi.StylePrimitive{
+		Underline: boolPtr(true),
+	},
+	Annotation: ansi.StylePrimitive{
+		Underline:      boolPtr(true),
+		UnderlineStyle: stringPtr("curly"),
+		UnderlineColor: stringPtr("$error"),
+	},
+	Sub: ansi.StylePrimitive{
+		Prefix: "_(",
+		Suffix: ")",
+	},
+	Sup: ansi.StylePrimitive{
+		Prefix: "^(",
+		Suffix: ")",
+	},
+	Spoiler: ansi.StylePrimitive{
+		Conceal:         boolPtr(true),
+		BackgroundColor: stringPtr("#414868"),
+	},
+	Summary: ansi.StylePrimitive{
+		Bold:  boolPtr(true),
+		Color: stringPtr("#7aa2f7"),
+	},
+	Details: ansi.StyleDetails{
+		StyleBlock: ansi.StyleBlock{
+			Indent: uintPtr(2),
+		},
+		Collapsed: "▶ ",
+		Expanded:  "▼ ",
+	},
 	HorizontalRule: ansi.StylePrimitive{
-		Color:  stringPtr("#565f89"),
+		Color:  stringPtr("$muted"),
 		Format: "\n--------\n",
 	},
 	Item: ansi.StylePrimitive{
@@ -89,11 +149,11 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 		Unticked:       "[ ] ",
 	},
 	Link: ansi.StylePrimitive{
-		Color:     stringPtr("#7aa2f7"),
+		Color:     stringPtr("$link"),
 		Underline: boolPtr(true),
 	},
 	LinkText: ansi.StylePrimitive{
-		Color: stringPtr("#2ac3de"),
+		Color: stringPtr("$link_text"),
 	},
 	Image: ansi.StylePrimitive{
 		Color:     stringPtr("#7aa2f7"),
@@ -105,7 +165,7 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 	},
 	Code: ansi.StyleBlock{
 		StylePrimitive: ansi.StylePrimitive{
-			Color: stringPtr("#9ece6a"),
+			Color: stringPtr("$code"),
 		},
 	},
 	CodeBlock: ansi.StyleCodeBlock{
@@ -115,6 +175,43 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 			},
 			Margin: uintPtr(defaultMargin),
 		},
+		Title: ansi.StylePrimitive{
+			Color: stringPtr("#c0caf5"),
+			Bold:  boolPtr(true),
+		},
+		LineNumber: ansi.StylePrimitive{
+			Color:  stringPtr("#3b4261"),
+			Suffix: " │ ",
+		},
+		Highlight: ansi.StylePrimitive{
+			BackgroundColor: stringPtr("#292e42"),
+		},
+		WrapMarker: "↪",
+		Ellipsis:   "…",
+		Diff: ansi.StyleDiff{
+			Added: ansi.StylePrimitive{
+				Color:           stringPtr("$success"),
+				BackgroundColor: stringPtr("#20303b"),
+			},
+			Removed: ansi.StylePrimitive{
+				Color:           stringPtr("$error"),
+				BackgroundColor: stringPtr("#37222c"),
+			},
+			Hunk: ansi.StylePrimitive{
+				Color: stringPtr("#7aa2f7"),
+			},
+			Header: ansi.StylePrimitive{
+				Color: stringPtr("#c0caf5"),
+				Bold:  boolPtr(true),
+			},
+			SyntaxHighlighting: true,
+		},
+		BorderColor: stringPtr("$border"),
+		Label: ansi.StylePrimitive{
+			Color:  stringPtr("#7aa2f7"),
+			Prefix: " ",
+			Suffix: " ",
+		},
 		Chroma: &ansi.Chroma{
 			Text: ansi.StylePrimitive{
 				Color: stringPtr("#a9b1d6"),
@@ -203,6 +300,37 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 			StylePrimitive: ansi.StylePrimitive{},
 		},
 	},
+	TOC: ansi.StyleTOC{
+		ID: ansi.StylePrimitive{
+			Color: stringPtr("$muted"),
+		},
+		LevelIndent: defaultListIndent,
+	},
+	Alerts: alertStyles("│ ", true, "$note", "$tip", "$important", "$warning", "$caution"),
+	FrontMatter: ansi.StyleFrontMatter{
+		Title: ansi.StylePrimitive{
+			Color: stringPtr("#7aa2f7"),
+			Bold:  boolPtr(true),
+		},
+		Date: ansi.StylePrimitive{
+			Color: stringPtr("#565f89"),
+		},
+		Tag: ansi.StylePrimitive{
+			Color:  stringPtr("#9ece6a"),
+			Prefix: "#",
+		},
+		Separator: " · ",
+	},
+	Math: ansi.StyleMath{
+		StyleBlock: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color: stringPtr("#7dcfff"),
+			},
+		},
+		Inline: ansi.StylePrimitive{
+			Color: stringPtr("#7dcfff"),
+		},
+	},
 	DefinitionDescription: ansi.StylePrimitive{
 		BlockPrefix: "\n🠶 ",
 	},